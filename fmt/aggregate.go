@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// AggregateFunc is a calculation over the numbers in a column.
+type AggregateFunc int
+
+const (
+	Sum AggregateFunc = iota
+	Average
+	Minimum
+	Maximum
+	Count
+)
+
+var aggregateNames = map[AggregateFunc]string{
+	Sum:     "Sum",
+	Average: "Average",
+	Minimum: "Min",
+	Maximum: "Max",
+	Count:   "Count",
+}
+
+func (a AggregateFunc) String() string {
+	if name, ok := aggregateNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("AggregateFunc(%d)", int(a))
+}
+
+// ParseAggregates reads a list like "sum,avg,count".
+func ParseAggregates(spec string) ([]AggregateFunc, error) {
+	var funcs []AggregateFunc
+	for _, name := range strings.Split(spec, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "":
+		case "sum", "total":
+			funcs = append(funcs, Sum)
+		case "avg", "average", "mean":
+			funcs = append(funcs, Average)
+		case "min":
+			funcs = append(funcs, Minimum)
+		case "max":
+			funcs = append(funcs, Maximum)
+		case "count":
+			funcs = append(funcs, Count)
+		default:
+			return nil, fmt.Errorf("unknown aggregate %q, expected sum, avg, min, max or count", name)
+		}
+	}
+	return funcs, nil
+}
+
+// apply runs the aggregate over values. ok is false when there is nothing
+// to take the average, minimum or maximum of.
+func (a AggregateFunc) apply(values []float64) (result float64, ok bool) {
+	if a == Count {
+		return float64(len(values)), true
+	}
+	if len(values) == 0 {
+		return 0, a == Sum
+	}
+
+	result = values[0]
+	for _, v := range values[1:] {
+		switch a {
+		case Sum, Average:
+			result += v
+		case Minimum:
+			result = math.Min(result, v)
+		case Maximum:
+			result = math.Max(result, v)
+		}
+	}
+	if a == Average {
+		result /= float64(len(values))
+	}
+	return result, true
+}
+
+// columnValues returns the numbers in column col of rows, skipping cells
+// that are empty or don't parse. "1,000,000" and "₦1,000.50" are understood.
+func columnValues(rows [][]string, col int) []float64 {
+	var values []float64
+	for _, row := range rows {
+		if v, ok := ParseNumber(cellAt(row, col)); ok {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// inferNumberFormat guesses how the numbers in a column were written, so
+// a total of "1,000,000" and "2,500,000" comes out as "3,500,000" and not 3.5e+06.
+func inferNumberFormat(rows [][]string, col int) NumberFormat {
+	var nf NumberFormat
+	prefixSeen := false
+	for _, row := range rows {
+		cell := strings.TrimSpace(cellAt(row, col))
+		if _, ok := ParseNumber(cell); !ok {
+			continue
+		}
+		if strings.Contains(cell, ",") {
+			nf.Grouping = true
+		}
+		if _, frac, ok := strings.Cut(cell, "."); ok {
+			nf.Precision = max(nf.Precision, len(frac))
+		}
+
+		prefix := strings.TrimLeft(cell, "+-")
+		prefix = prefix[:len(prefix)-len(strings.TrimLeftFunc(prefix, func(r rune) bool { return unicode.Is(unicode.Sc, r) }))]
+		if !prefixSeen {
+			nf.Prefix, prefixSeen = prefix, true
+		} else if nf.Prefix != prefix {
+			nf.Prefix = ""
+		}
+	}
+	return nf
+}
+
+// aggregateRow builds one summary row: label in the label column and the
+// result of fn in every numeric column.
+func (t TableData) aggregateRow(rows [][]string, fn AggregateFunc, label string, labelCol int, numeric []bool) []string {
+	out := make([]string, t.columnCount())
+	if labelCol >= 0 {
+		out[labelCol] = label
+	}
+	for col, isNum := range numeric {
+		if !isNum || col == labelCol {
+			continue
+		}
+		result, ok := fn.apply(columnValues(rows, col))
+		if !ok {
+			continue
+		}
+		out[col] = t.formatAggregate(result, fn, rows, col)
+	}
+	return out
+}
+
+func (t TableData) formatAggregate(v float64, fn AggregateFunc, rows [][]string, col int) string {
+	switch {
+	case fn == Count:
+		return strconv.Itoa(int(v))
+	case t.column(col).Number != nil:
+		// the column's own NumberFormat is applied when rendering
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	nf := inferNumberFormat(rows, col)
+	if fn == Average && v != math.Trunc(v) {
+		nf.Precision = max(nf.Precision, 2)
+	}
+	return nf.Format(v)
+}
+
+// summaryLayout works out which columns get numbers and where the label goes:
+// the first column that isn't numeric, or none when they all are.
+func (t TableData) summaryLayout() (numeric []bool, labelCol int) {
+	numeric = make([]bool, t.columnCount())
+	labelCol = -1
+	for i := range numeric {
+		numeric[i] = t.isNumeric(i)
+		if !numeric[i] && labelCol < 0 {
+			labelCol = i
+		}
+	}
+	return numeric, labelCol
+}
+
+// WithTotals returns a copy of the table with a footer row for every fn,
+// calculated over each numeric column. The footer is drawn under the body,
+// with a rule in between like the one under the header.
+func (t TableData) WithTotals(funcs ...AggregateFunc) TableData {
+	numeric, labelCol := t.summaryLayout()
+	t.Footer, t.plainFooter = nil, nil
+	for _, fn := range funcs {
+		t.Footer = append(t.Footer, t.aggregateRow(t.Rows, fn, fn.String(), labelCol, numeric))
+		t.plainFooter = append(t.plainFooter, fn == Count)
+	}
+	return t
+}
+
+// GroupBy returns a copy of the table with rows grouped by the values of
+// column, in the order each value first appears. After every group come
+// subtotal rows, one per fn, labelled like "Lagos Sum", and the grand totals
+// over all rows go in the footer labelled like "Total Sum".
+// Count subtotals are shown as they are, like the Count footer, and paging
+// only counts the grouped rows, not the subtotals.
+func (t TableData) GroupBy(column string, funcs ...AggregateFunc) (TableData, error) {
+	groupCol, err := t.lookupColumn(column)
+	if err != nil {
+		return t, err
+	}
+	numeric, labelCol := t.summaryLayout()
+	if !numeric[groupCol] {
+		// The subtotal label goes under the value it totals.
+		labelCol = groupCol
+	}
+
+	var order []string
+	groups := map[string][][]string{}
+	for _, row := range t.Rows {
+		key := cellAt(row, groupCol)
+		if _, seen := groups[key]; !seen {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], row)
+	}
+
+	var rows [][]string
+	var subtotals, plain []bool
+	for _, key := range order {
+		rows = append(rows, groups[key]...)
+		subtotals = append(subtotals, make([]bool, len(groups[key]))...)
+		plain = append(plain, make([]bool, len(groups[key]))...)
+		for _, fn := range funcs {
+			label := strings.TrimSpace(key + " " + fn.String())
+			rows = append(rows, t.aggregateRow(groups[key], fn, label, labelCol, numeric))
+			subtotals = append(subtotals, true)
+			plain = append(plain, fn == Count)
+		}
+	}
+
+	t.Footer, t.plainFooter = nil, nil
+	for _, fn := range funcs {
+		t.Footer = append(t.Footer, t.aggregateRow(t.Rows, fn, "Total "+fn.String(), labelCol, numeric))
+		t.plainFooter = append(t.plainFooter, fn == Count)
+	}
+	t.Rows, t.subtotalRows, t.plainRows = rows, subtotals, plain
+	return t, nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// ANSI Colors
+
+// Terminals change color when they see an escape sequence like "\x1b[1;31m"
+// (ESC [ then SGR codes separated by ";" then "m"). SGR is "Select Graphic
+// Rendition", 1 is bold and 31 is red. "\x1b[0m" resets everything.
+
+const (
+	escape    = '\x1b'
+	ansiReset = "\x1b[0m"
+)
+
+// SGR codes that can be used in a Theme, and combined with ";": "1;31" is bold red.
+const (
+	ANSIBold      = "1"
+	ANSIDim       = "2"
+	ANSIItalic    = "3"
+	ANSIUnderline = "4"
+	ANSIReverse   = "7"
+	ANSIRed       = "31"
+	ANSIGreen     = "32"
+	ANSIYellow    = "33"
+	ANSIBlue      = "34"
+	ANSIMagenta   = "35"
+	ANSICyan      = "36"
+	ANSIGray      = "90"
+	ANSIStripe    = "48;5;236" // dark gray background, for zebra rows
+)
+
+// paint wraps s in the escape codes for sgr, or returns it as is when sgr is empty.
+func paint(s, sgr string) string {
+	if sgr == "" || s == "" {
+		return s
+	}
+	return "\x1b[" + sgr + "m" + s + ansiReset
+}
+
+// joinSGR combines codes, skipping the empty ones.
+func joinSGR(codes ...string) string {
+	var parts []string
+	for _, c := range codes {
+		if c != "" {
+			parts = append(parts, c)
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// ansiLen is the length of the escape sequence at the start of s:
+// CSI sequences ("\x1b[...m") end at a byte in '@'..'~', OSC sequences
+// ("\x1b]...") at BEL or "\x1b\\". Anything else is ESC plus one byte.
+func ansiLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= '@' && s[i] <= '~' {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == escape && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+// stripANSI removes every escape sequence from s.
+func stripANSI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] == escape {
+			i += ansiLen(s[i:])
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
+
+// ColorMode says whether a TextRenderer uses its Theme.
+type ColorMode int
+
+const (
+	ColorAuto   ColorMode = iota // color only on a terminal, and only without NO_COLOR
+	ColorAlways                  // color even when writing to a file or pipe
+	ColorNever                   // never color
+)
+
+// ColorEnabled reports whether colored output makes sense for w: it must be
+// a terminal, and the NO_COLOR environment variable must not be set
+// (see https://no-color.org).
+func ColorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f.Fd())
+}
+
+func (m ColorMode) enabled(w io.Writer) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	return ColorEnabled(w)
+}
+
+// Theme says how a TextRenderer colors a table. Every field holds SGR codes
+// like ANSIBold or "1;31", an empty string leaves that part alone.
+// Codes add up: a red cell on a zebra row is red on the stripe color.
+type Theme struct {
+	Header  string            // header cells
+	Zebra   string            // every second body row
+	Footer  string            // footer rows
+	Columns map[string]string // body cells of a column, keyed by header
+
+	// Cell picks codes for a single body cell from its header and raw value,
+	// e.g. red for negative numbers. It may be nil.
+	Cell func(header, value string) string
+}
+
+// DefaultTheme has bold headers, striped rows and negative numbers in red.
+var DefaultTheme = &Theme{
+	Header: ANSIBold,
+	Zebra:  ANSIStripe,
+	Footer: ANSIBold,
+	Cell:   NegativeRed,
+}
+
+// NegativeRed is a Theme.Cell function that colors negative numbers red.
+func NegativeRed(header, value string) string {
+	if v, ok := ParseNumber(value); ok && v < 0 {
+		return ANSIRed
+	}
+	return ""
+}
+
+// body returns the codes for body cell (row, col).
+func (th *Theme) body(t TableData, row, col int) string {
+	zebra := ""
+	if row%2 == 1 {
+		zebra = th.Zebra
+	}
+	header := t.headerAt(col)
+	cell := ""
+	if th.Cell != nil {
+		cell = th.Cell(header, cellAt(t.Rows[row], col))
+	}
+	return joinSGR(zebra, th.Columns[header], cell)
+}
+
+// rowCodes returns the same codes for every column of a row.
+func rowCodes(n int, sgr string) []string {
+	if sgr == "" {
+		return nil
+	}
+	codes := make([]string, n)
+	for i := range codes {
+		codes[i] = sgr
+	}
+	return codes
+}
@@ -0,0 +1,184 @@
+package main
+
+import "strings"
+
+// Rule is one horizontal line of a table frame, e.g. "├─────┼─────┤".
+// A Rule with an empty Fill is not drawn at all.
+type Rule struct {
+	Left, Fill, Cross, Right string
+}
+
+// BorderStyle is the set of characters a TextRenderer draws the frame with.
+type BorderStyle struct {
+	Left, Middle, Right string // vertical lines before, between and after cells
+	Padding             int    // spaces between a vertical line and the cell text
+
+	Top    Rule // above the header
+	Header Rule // between the header and the body
+	Row    Rule // between body rows, only drawn when RowSeparators is on
+	Footer Rule // between the body and the footer, the Header rule when empty
+	Bottom Rule // below the last row
+
+	// MarkAlignment puts ':' into the Header rule the way Markdown
+	// does, e.g. "|---:|" for a right-aligned column.
+	MarkAlignment bool
+}
+
+// BorderPipe is the original Print layout: a "|" in front of every cell, and
+// a dashed line above the footer so totals don't pass for data.
+//
+//	|Name|Age
+//	|Ada | 36
+//	|----|---
+//	|Sum | 36
+var BorderPipe = &BorderStyle{Left: "|", Middle: "|", Footer: Rule{"|", "-", "|", ""}}
+
+// BorderASCII only uses characters every terminal and log viewer can show.
+//
+//	+------+-----+
+//	| Name | Age |
+//	+------+-----+
+//	| Ada  |  36 |
+//	+------+-----+
+var BorderASCII = &BorderStyle{
+	Left: "|", Middle: "|", Right: "|", Padding: 1,
+	Top:    Rule{"+", "-", "+", "+"},
+	Header: Rule{"+", "-", "+", "+"},
+	Row:    Rule{"+", "-", "+", "+"},
+	Bottom: Rule{"+", "-", "+", "+"},
+}
+
+// BorderLight uses the thin Unicode box-drawing characters.
+//
+//	┌──────┬─────┐
+//	│ Name │ Age │
+//	├──────┼─────┤
+//	│ Ada  │  36 │
+//	└──────┴─────┘
+var BorderLight = &BorderStyle{
+	Left: "│", Middle: "│", Right: "│", Padding: 1,
+	Top:    Rule{"┌", "─", "┬", "┐"},
+	Header: Rule{"├", "─", "┼", "┤"},
+	Row:    Rule{"├", "─", "┼", "┤"},
+	Bottom: Rule{"└", "─", "┴", "┘"},
+}
+
+// BorderHeavy is BorderLight drawn with thick lines.
+var BorderHeavy = &BorderStyle{
+	Left: "┃", Middle: "┃", Right: "┃", Padding: 1,
+	Top:    Rule{"┏", "━", "┳", "┓"},
+	Header: Rule{"┣", "━", "╋", "┫"},
+	Row:    Rule{"┣", "━", "╋", "┫"},
+	Bottom: Rule{"┗", "━", "┻", "┛"},
+}
+
+// BorderDouble is BorderLight drawn with double lines.
+var BorderDouble = &BorderStyle{
+	Left: "║", Middle: "║", Right: "║", Padding: 1,
+	Top:    Rule{"╔", "═", "╦", "╗"},
+	Header: Rule{"╠", "═", "╬", "╣"},
+	Row:    Rule{"╠", "═", "╬", "╣"},
+	Bottom: Rule{"╚", "═", "╩", "╝"},
+}
+
+// BorderRounded is BorderLight with rounded corners.
+var BorderRounded = &BorderStyle{
+	Left: "│", Middle: "│", Right: "│", Padding: 1,
+	Top:    Rule{"╭", "─", "┬", "╮"},
+	Header: Rule{"├", "─", "┼", "┤"},
+	Row:    Rule{"├", "─", "┼", "┤"},
+	Bottom: Rule{"╰", "─", "┴", "╯"},
+}
+
+// BorderMarkdown draws a table that is also a valid GitHub Markdown table,
+// so it looks right both in a terminal and in a rendered README.
+// Row separators are not drawn in this style, Markdown has no syntax for them.
+var BorderMarkdown = &BorderStyle{
+	Left: "|", Middle: "|", Right: "|", Padding: 1,
+	Header:        Rule{"|", "-", "|", "|"},
+	MarkAlignment: true,
+}
+
+// BorderNone has no vertical lines, only a dashed line under the header.
+//
+//	Name  Age
+//	----  ---
+//	Ada    36
+var BorderNone = &BorderStyle{
+	Middle: "  ",
+	Header: Rule{"", "-", "  ", ""},
+	Row:    Rule{"", " ", "  ", ""},
+}
+
+// BorderStyles lets a style be picked by name, e.g. from a command-line flag.
+var BorderStyles = map[string]*BorderStyle{
+	"pipe":     BorderPipe,
+	"ascii":    BorderASCII,
+	"light":    BorderLight,
+	"heavy":    BorderHeavy,
+	"double":   BorderDouble,
+	"rounded":  BorderRounded,
+	"markdown": BorderMarkdown,
+	"none":     BorderNone,
+}
+
+// footerRule is the rule drawn above the footer.
+func (b *BorderStyle) footerRule() Rule {
+	if b.Footer.Fill != "" {
+		return b.Footer
+	}
+	return b.Header
+}
+
+// rule renders r across columns of the given widths.
+// It returns "" when the rule is not part of the style.
+func (b *BorderStyle) rule(r Rule, widths []int, aligns []Alignment, markAlignment bool) string {
+	if r.Fill == "" {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(r.Left)
+	for i, w := range widths {
+		if i > 0 {
+			sb.WriteString(r.Cross)
+		}
+		fill := strings.Repeat(r.Fill, w+2*b.Padding)
+		if markAlignment && i < len(aligns) {
+			fill = markAligned(fill, aligns[i])
+		}
+		sb.WriteString(fill)
+	}
+	sb.WriteString(r.Right)
+	return sb.String()
+}
+
+// markAligned swaps the ends of a "-----" run for colons, like Markdown: ":---:".
+func markAligned(fill string, align Alignment) string {
+	if len(fill) < 2 {
+		return fill
+	}
+	switch align {
+	case AlignLeft:
+		return ":" + fill[1:]
+	case AlignRight:
+		return fill[:len(fill)-1] + ":"
+	case AlignCenter:
+		return ":" + fill[1:len(fill)-1] + ":"
+	}
+	return fill
+}
+
+// line renders one line of cells, each already fitted to its column width.
+func (b *BorderStyle) line(cells []string) string {
+	pad := strings.Repeat(" ", b.Padding)
+	var sb strings.Builder
+	sb.WriteString(b.Left)
+	for i, cell := range cells {
+		if i > 0 {
+			sb.WriteString(b.Middle)
+		}
+		sb.WriteString(pad + cell + pad)
+	}
+	sb.WriteString(b.Right)
+	return sb.String()
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// stringList is a flag that can be given more than once: -filter a -filter b
+type stringList []string
+
+func (s *stringList) String() string { return strings.Join(*s, ", ") }
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// tableFlags are the command-line flags for rendering a table.
+type tableFlags struct {
+	input       string
+	inputFormat string
+	ragged      string
+	placeholder string
+	sort        string
+	filters     stringList
+	page        int
+	perPage     int
+	offset      int
+	limit       int
+	totals      string
+	groupBy     string
+	border      string
+	format      string
+	width       int
+	expanded    string
+	color       string
+}
+
+// readers turn text back into a TableData, see parse.go.
+var readers = map[string]func(io.Reader) (TableData, error){
+	"csv":      ReadCSV,
+	"markdown": ReadMarkdown,
+	"text":     ReadText,
+}
+
+var renderers = map[string]Renderer{
+	"markdown": MarkdownRenderer{},
+	"csv":      CSVRenderer{},
+	"tsv":      TSVRenderer{},
+	"html":     HTMLRenderer{},
+	"json":     JSONRenderer{Indent: "  "},
+}
+
+func registerTableFlags(fs *flag.FlagSet) *tableFlags {
+	f := &tableFlags{}
+	fs.StringVar(&f.input, "input", "", "read the table from this file instead of using the built-in one (- for stdin)")
+	fs.StringVar(&f.inputFormat, "input-format", "", "format of -input: "+strings.Join(sortedKeys(readers), ", ")+" (default from the file extension, text otherwise)")
+	fs.StringVar(&f.ragged, "ragged", "pad", "what to do with rows that don't have one cell per header: fail, pad or truncate")
+	fs.StringVar(&f.placeholder, "placeholder", "", "text for missing cells with -ragged pad or truncate")
+	fs.StringVar(&f.sort, "sort", "", `sort rows by columns, e.g. "City,-Age" (- for descending)`)
+	fs.Var(&f.filters, "filter", `keep rows matching "column op value", e.g. "Age >= 26" (repeatable)`)
+	fs.StringVar(&f.totals, "totals", "", `footer rows for the numeric columns, e.g. "sum,avg" (sum, avg, min, max, count)`)
+	fs.StringVar(&f.groupBy, "group-by", "", "group rows by this column and add a subtotal after each group (uses -totals, sum by default)")
+	fs.IntVar(&f.page, "page", 0, "show only this page of rows (1-based), see -per-page")
+	fs.IntVar(&f.perPage, "per-page", 20, "rows per page when -page is set")
+	fs.IntVar(&f.offset, "offset", 0, "skip this many rows")
+	fs.IntVar(&f.limit, "limit", 0, "show at most this many rows (0 means all)")
+	fs.StringVar(&f.border, "border", "pipe", "border style for text output: "+strings.Join(sortedKeys(BorderStyles), ", "))
+	fs.StringVar(&f.format, "format", "text", "output format: text, "+strings.Join(sortedKeys(renderers), ", "))
+	fs.StringVar(&f.expanded, "expanded", "auto", "draw each row as a block of header | value lines: on, off or auto (when the table is too wide)")
+	fs.StringVar(&f.color, "color", "auto", "color text output: always, never or auto (only on a terminal, and not when NO_COLOR is set)")
+	fs.IntVar(&f.width, "width", 0, "maximum table width for text output (0 means the terminal width)")
+	return f
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// load reads the table named by -input, or returns t when there is none.
+func (f *tableFlags) load(t TableData) (TableData, error) {
+	if f.input == "" {
+		return t, nil
+	}
+
+	format := f.inputFormat
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(f.input)) {
+		case ".csv":
+			format = "csv"
+		case ".md", ".markdown":
+			format = "markdown"
+		default:
+			format = "text"
+		}
+	}
+	read, ok := readers[format]
+	if !ok {
+		return t, fmt.Errorf("unknown input format %q", format)
+	}
+
+	if f.input == "-" {
+		return read(os.Stdin)
+	}
+	file, err := os.Open(f.input)
+	if err != nil {
+		return t, err
+	}
+	defer file.Close()
+
+	t, err = read(file)
+	if err != nil {
+		return t, fmt.Errorf("%s: %w", f.input, err)
+	}
+	return t, nil
+}
+
+// render repairs, filters, sorts, totals and slices t as the flags say, in that
+// order, and writes it to w. Totals are over every matching row, not just the page shown.
+// Paged text output gets a "showing 1–20 of 143" line at the end.
+func (f *tableFlags) render(w io.Writer, t TableData) error {
+	mode, err := ParseRaggedMode(f.ragged)
+	if err != nil {
+		return err
+	}
+	if t, err = t.Repair(RaggedPolicy{Mode: mode, Placeholder: f.placeholder}); err != nil {
+		return err
+	}
+
+	for _, expr := range f.filters {
+		if t, err = t.Where(expr); err != nil {
+			return err
+		}
+	}
+
+	if f.sort != "" {
+		keys, err := ParseSortKeys(f.sort)
+		if err != nil {
+			return err
+		}
+		if t, err = t.Sort(keys...); err != nil {
+			return err
+		}
+	}
+
+	funcs, err := ParseAggregates(f.totals)
+	if err != nil {
+		return err
+	}
+	switch {
+	case f.groupBy != "":
+		if len(funcs) == 0 {
+			funcs = []AggregateFunc{Sum}
+		}
+		if t, err = t.GroupBy(f.groupBy, funcs...); err != nil {
+			return err
+		}
+	case len(funcs) > 0:
+		t = t.WithTotals(funcs...)
+	}
+
+	var page *PageInfo
+	switch {
+	case f.page > 0:
+		var info PageInfo
+		t, info = t.Paginate(f.page, f.perPage)
+		page = &info
+	case f.offset > 0 || f.limit > 0:
+		var info PageInfo
+		t, info = t.Slice(f.offset, f.limit)
+		page = &info
+	}
+
+	if f.format != "text" {
+		r, ok := renderers[f.format]
+		if !ok {
+			return fmt.Errorf("unknown format %q", f.format)
+		}
+		return t.Render(w, r)
+	}
+
+	border, ok := BorderStyles[f.border]
+	if !ok {
+		return fmt.Errorf("unknown border style %q", f.border)
+	}
+	expanded, ok := map[string]ExpandMode{"off": ExpandOff, "on": ExpandOn, "auto": ExpandAuto}[f.expanded]
+	if !ok {
+		return fmt.Errorf("unknown -expanded value %q, expected on, off or auto", f.expanded)
+	}
+	color, ok := map[string]ColorMode{"auto": ColorAuto, "always": ColorAlways, "never": ColorNever}[f.color]
+	if !ok {
+		return fmt.Errorf("unknown -color value %q, expected always, never or auto", f.color)
+	}
+	width := f.width
+	if width == 0 {
+		width = TerminalWidth(os.Stdout)
+	}
+	r := TextRenderer{Border: border, Width: width, Expanded: expanded, Theme: DefaultTheme, Color: color}
+	if err := t.Render(w, r); err != nil {
+		return err
+	}
+	if page != nil {
+		_, err := fmt.Fprintln(w, page)
+		return err
+	}
+	return nil
+}
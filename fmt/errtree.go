@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Error Trees
+
+// fmt.Errorf with %w wraps one error in another, errors.Join and a format
+// with several %w put many errors under one. Printed with %v the whole tree
+// comes out as a single line. ErrorTree walks it instead, through
+// Unwrap() error and Unwrap() []error, and WriteErrorTree draws it:
+//
+//	*fmt.wrapError: loading people
+//	└── main.ValidationErrors  count=2
+//	    ├── main.ValidationError: Name: is required  code="required" field="Name" value=""
+//	    └── main.ValidationError: Age: must be at most 150  code="max" field="Age" value=200
+//
+// Each node shows its own part of the message only, the part its causes
+// added is left to them. Errors from this package and a few from the
+// standard library also show their fields.
+
+// ErrorNode is one error in a tree, with the errors it wraps as Causes.
+type ErrorNode struct {
+	Type    string         `json:"type"`              // the concrete type, as %T prints it
+	Message string         `json:"message,omitempty"` // the message without the causes' part
+	Fields  map[string]any `json:"fields,omitempty"`
+	Causes  []*ErrorNode   `json:"causes,omitempty"`
+}
+
+// ErrorTree returns the tree of err, or nil when err is nil.
+func ErrorTree(err error) *ErrorNode {
+	if err == nil {
+		return nil
+	}
+	var causes []error
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		causes = []error{e.Unwrap()}
+	case interface{ Unwrap() []error }:
+		causes = e.Unwrap()
+	}
+
+	n := &ErrorNode{Type: fmt.Sprintf("%T", err), Fields: errorFields(err)}
+	var messages []string
+	for _, cause := range causes {
+		if child := ErrorTree(cause); child != nil {
+			n.Causes = append(n.Causes, child)
+			messages = append(messages, cause.Error())
+		}
+	}
+	n.Message = ownMessage(err.Error(), messages)
+	return n
+}
+
+// ownMessage takes the causes' messages off msg: "loading: open x: not found"
+// is "loading" when the cause is "open x: not found", and errors.Join and
+// ValidationErrors are nothing but the messages of their causes.
+func ownMessage(msg string, causes []string) string {
+	switch {
+	case len(causes) == 0:
+		return msg
+	case msg == strings.Join(causes, "\n"), msg == strings.Join(causes, "; "):
+		return ""
+	case len(causes) == 1:
+		if own, ok := strings.CutSuffix(msg, ": "+causes[0]); ok {
+			return own
+		}
+	}
+	return msg
+}
+
+// errorFields picks the fields worth showing out of the error types it knows.
+func errorFields(err error) map[string]any {
+	switch e := err.(type) {
+	case ValidationError:
+		return map[string]any{"field": e.Field, "code": e.Code, "value": loggable(e.Value)}
+	case *ValidationError:
+		return errorFields(*e)
+	case ValidationErrors:
+		return map[string]any{"count": len(e)}
+	case RowError:
+		return map[string]any{"row": e.Row + 1, "column": e.Column + 1, "got": e.Got, "want": e.Want, "header": e.Header}
+	case *RowError:
+		return errorFields(*e)
+	case RowErrors:
+		return map[string]any{"count": len(e)}
+	case *ParseError:
+		return map[string]any{"line": e.Line, "column": e.Column}
+	case *PlaceholderError:
+		return map[string]any{"line": e.Line, "column": e.Column, "placeholder": e.Placeholder}
+	case ProblemDetails:
+		fields := map[string]any{"status": e.Status}
+		if e.Type != "" {
+			fields["type"] = e.Type
+		}
+		if e.Instance != "" {
+			fields["instance"] = e.Instance
+		}
+		return fields
+	case *fs.PathError:
+		return map[string]any{"op": e.Op, "path": e.Path}
+	case *strconv.NumError:
+		return map[string]any{"func": e.Func, "num": e.Num}
+	}
+	return nil
+}
+
+// loggable is v, or v as %v prints it when JSON can't encode it (a channel,
+// a func, NaN), so one odd value doesn't keep the whole tree out of the log.
+func loggable(v any) any {
+	if _, err := marshalJSON(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return v
+}
+
+// WriteErrorTree draws the tree of err on w, one error per line.
+func WriteErrorTree(w io.Writer, err error) error {
+	n := ErrorTree(err)
+	if n == nil {
+		return nil
+	}
+	ew := &errWriter{w: w}
+	n.write(ew, "", "")
+	return ew.err
+}
+
+// write prints n after first, and its causes under it after indent.
+func (n *ErrorNode) write(ew *errWriter, first, indent string) {
+	line := n.Type
+	if n.Message != "" {
+		// a message over several lines would break up the tree
+		line += ": " + strings.ReplaceAll(n.Message, "\n", "; ")
+	}
+	if fields := n.fieldText(); fields != "" {
+		line += "  " + fields
+	}
+	ew.print(first + line + "\n")
+
+	for i, cause := range n.Causes {
+		if i == len(n.Causes)-1 {
+			cause.write(ew, indent+"└── ", indent+"    ")
+		} else {
+			cause.write(ew, indent+"├── ", indent+"│   ")
+		}
+	}
+}
+
+// fieldText is the fields as name=value, by name, strings quoted.
+func (n *ErrorNode) fieldText() string {
+	names := make([]string, 0, len(n.Fields))
+	for name := range n.Fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		if s, ok := n.Fields[name].(string); ok {
+			parts[i] = fmt.Sprintf("%s=%q", name, s)
+		} else {
+			parts[i] = fmt.Sprintf("%s=%v", name, n.Fields[name])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// WriteErrorTreeJSON writes the tree of err as one line of JSON, for logs.
+// A nil err is written as null.
+func WriteErrorTreeJSON(w io.Writer, err error) error {
+	data, err := marshalJSON(ErrorTree(err))
+	if err != nil {
+		return fmt.Errorf("encoding error tree: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expanded Display
+
+// A table with many columns doesn't fit on a terminal however much the
+// columns are squeezed. psql's \x solves that by turning every row on its
+// side: one "Header | value" line per column, and a separator per record.
+//
+//	-[ RECORD 1 ]---+-----------------
+//	Name            | Daniel Okoronkwo
+//	Age             |               25
+//	Account Balance |    ₦1,000,000.00
+
+// ExpandMode says when TextRenderer draws rows as expanded records.
+type ExpandMode int
+
+const (
+	ExpandOff  ExpandMode = iota // always draw a normal table
+	ExpandOn                     // always draw one record per row
+	ExpandAuto                   // draw records only when the table is wider than Width
+)
+
+// expand decides if the table is drawn as records: always with ExpandOn,
+// and with ExpandAuto when the natural layout doesn't fit the width budget.
+func (r TextRenderer) expand(t TableData, border *BorderStyle) bool {
+	switch r.Expanded {
+	case ExpandOn:
+		return true
+	case ExpandAuto:
+		if r.Width <= 0 {
+			return false
+		}
+		total := border.overhead(t.columnCount())
+		for _, w := range t.ColumnWidths() {
+			total += w
+		}
+		return total > r.Width
+	}
+	return false
+}
+
+// renderExpanded draws every row as a block of "Header | value" lines.
+// It is a two-column table per record, so widths, alignment, wrapping and
+// the border all work the same way they do for the normal layout.
+// With a theme the header column gets the Header codes and the values the
+// same codes they would get in the normal layout.
+func (r TextRenderer) renderExpanded(ew *errWriter, t TableData, border *BorderStyle, theme *Theme) {
+	n := t.columnCount()
+	headers := make([]string, n)
+	for i := range headers {
+		headers[i] = t.headerAt(i)
+	}
+
+	headerWidth, valueWidth := 0, 0
+	for _, h := range headers {
+		headerWidth = max(headerWidth, cellWidth(h))
+	}
+	for i, w := range t.ColumnWidths() {
+		if i < n {
+			valueWidth = max(valueWidth, w)
+		}
+	}
+
+	layout := textLayout{
+		border:   border,
+		widths:   t.fitToWidth([]int{headerWidth, valueWidth}, border, r.Width),
+		aligns:   []Alignment{AlignLeft, AlignLeft},
+		overflow: r.Overflow,
+	}
+	aligns := t.alignments()
+
+	writeRecord := func(title string, row []string, first bool, codes func(col int) string) {
+		rule := border.Header
+		if first && border.Top.Fill != "" {
+			rule = border.Top
+		}
+		ew.print(recordTitle(layout.border.rule(rule, layout.widths, nil, false), title, layout.widths) + "\n")
+		for i, header := range headers {
+			layout.aligns[1] = aligns[i]
+			if theme == nil {
+				layout.writeRow(ew, []string{header, cellAt(row, i)})
+				continue
+			}
+			layout.writeStyledRow(ew, []string{header, cellAt(row, i)}, []string{theme.Header, codes(i)})
+		}
+	}
+
+	for i, row := range t.displayRows() {
+		writeRecord(fmt.Sprintf("RECORD %d", i+1), row, i == 0, func(col int) string { return theme.body(t, i, col) })
+	}
+	for i, row := range t.displayFooter() {
+		writeRecord(fmt.Sprintf("FOOTER %d", i+1), row, i == 0 && len(t.Rows) == 0, func(int) string { return theme.Footer })
+	}
+	layout.writeRule(ew, border.Bottom, false)
+}
+
+// recordTitle writes "[ RECORD 1 ]" over the start of a rule, like psql:
+// "+----+----+" becomes "+[ RECORD 1 ]-+". Styles without rules get
+// psql's own "-[ RECORD 1 ]-----".
+func recordTitle(rule, title string, widths []int) string {
+	label := "[ " + title + " ]"
+	if rule == "" {
+		total := 2
+		for _, w := range widths {
+			total += w
+		}
+		return "-" + label + strings.Repeat("-", max(total-displayWidth(label)-1, 0))
+	}
+
+	runes := []rune(rule)
+	if len(runes) < 2 {
+		return rule + label
+	}
+	// keep the corner, overwrite what comes after it
+	rest := runes[1:]
+	labelRunes := []rune(label)
+	if len(rest) <= len(labelRunes) {
+		return string(runes[:1]) + label
+	}
+	return string(runes[:1]) + label + string(rest[len(labelRunes):])
+}
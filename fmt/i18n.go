@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Localized Messages
+
+// Sentences like Person.String are looked up by message ID in a Catalog
+// instead of being written out in English. Each locale has its own template,
+// a fmt format string that uses argument indexes (%[1]s) so a translation
+// can put the arguments in a different order than English does.
+//
+// Languages don't agree on plurals either: English says "1 year" but
+// "0 years", French says "0 an" and "1 an". A Message has a template for
+// each plural form, and the locale's PluralRule picks one from the count.
+
+// PluralForm is the grammatical number a count needs.
+type PluralForm int
+
+const (
+	PluralOther PluralForm = iota // "25 years", every language has it
+	PluralOne                     // "1 year"
+)
+
+// PluralRule picks the plural form for a count in one language.
+type PluralRule func(n int) PluralForm
+
+// OneIsSingular is the rule for English and German: only 1 is singular.
+func OneIsSingular(n int) PluralForm {
+	if n == 1 || n == -1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// ZeroAndOneAreSingular is the rule for French: 0 and 1 are singular.
+func ZeroAndOneAreSingular(n int) PluralForm {
+	if n >= -1 && n <= 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// Message is the template for a message in one locale. One is only used
+// for counts the PluralRule calls singular, and falls back to Other.
+type Message struct {
+	One   string
+	Other string
+}
+
+func (m Message) form(f PluralForm) string {
+	if f == PluralOne && m.One != "" {
+		return m.One
+	}
+	return m.Other
+}
+
+// FallbackLocale is used for messages that have no translation.
+const FallbackLocale = "en"
+
+// Catalog holds messages by ID and locale.
+type Catalog struct {
+	messages map[string]map[string]Message // message ID -> locale -> message
+	plurals  map[string]PluralRule         // locale -> rule
+}
+
+// NewCatalog returns an empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{messages: map[string]map[string]Message{}, plurals: map[string]PluralRule{}}
+}
+
+// Set adds the message id for locale.
+func (c *Catalog) Set(locale, id string, msg Message) {
+	if c.messages[id] == nil {
+		c.messages[id] = map[string]Message{}
+	}
+	c.messages[id][normalizeLocale(locale)] = msg
+}
+
+// SetPluralRule sets the plural rule of locale, OneIsSingular is used for
+// locales without one.
+func (c *Catalog) SetPluralRule(locale string, rule PluralRule) {
+	c.plurals[normalizeLocale(locale)] = rule
+}
+
+// lookup finds the message for the most specific locale that has it:
+// "fr_CA", then "fr", then FallbackLocale.
+func (c *Catalog) lookup(locale, id string) (Message, string, bool) {
+	translations := c.messages[id]
+	for _, l := range localeChain(locale) {
+		if msg, ok := translations[l]; ok {
+			return msg, l, true
+		}
+	}
+	return Message{}, "", false
+}
+
+func (c *Catalog) pluralRule(locale string) PluralRule {
+	for _, l := range localeChain(locale) {
+		if rule, ok := c.plurals[l]; ok {
+			return rule
+		}
+	}
+	return OneIsSingular
+}
+
+// Printer formats the messages of a Catalog in one locale.
+type Printer struct {
+	catalog *Catalog
+	locale  string
+}
+
+// Printer returns a Printer for locale, e.g. "fr", "de_DE" or "pt_BR.UTF-8".
+func (c *Catalog) Printer(locale string) *Printer {
+	return &Printer{catalog: c, locale: normalizeLocale(locale)}
+}
+
+// Locale is the locale the Printer was made for.
+func (p *Printer) Locale() string {
+	return p.locale
+}
+
+// Sprintf formats message id with args. A message missing from the catalog
+// comes out as its ID followed by the arguments, so it is easy to spot.
+func (p *Printer) Sprintf(id string, args ...any) string {
+	msg, _, ok := p.catalog.lookup(p.locale, id)
+	if !ok {
+		return missingMessage(id, args)
+	}
+	return fmt.Sprintf(msg.Other, args...)
+}
+
+// Plural is Sprintf for a message with plural forms, the form is chosen
+// for n. n is not passed to the template, put it in args if it is needed.
+func (p *Printer) Plural(id string, n int, args ...any) string {
+	msg, locale, ok := p.catalog.lookup(p.locale, id)
+	if !ok {
+		return missingMessage(id, args)
+	}
+	return fmt.Sprintf(msg.form(p.catalog.pluralRule(locale)(n)), args...)
+}
+
+func missingMessage(id string, args []any) string {
+	return strings.TrimSpace(fmt.Sprintln(append([]any{id}, args...)...))
+}
+
+// normalizeLocale turns "fr-FR", "fr_FR.UTF-8" and "de_DE@euro" into
+// "fr_FR" and "de_DE". The "C" and "POSIX" locales are English.
+func normalizeLocale(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	locale = strings.ReplaceAll(locale, "-", "_")
+	lang, region, hasRegion := strings.Cut(locale, "_")
+	lang = strings.ToLower(lang)
+	switch lang {
+	case "", "c", "posix":
+		return FallbackLocale
+	}
+	if hasRegion {
+		return lang + "_" + strings.ToUpper(region)
+	}
+	return lang
+}
+
+// localeChain lists the locales to try for a normalized locale.
+func localeChain(locale string) []string {
+	chain := []string{locale}
+	if lang, _, ok := strings.Cut(locale, "_"); ok {
+		chain = append(chain, lang)
+	}
+	return append(chain, FallbackLocale)
+}
+
+// DetectLocale reads the locale from the environment the way POSIX programs
+// do for messages: LC_ALL, then LC_MESSAGES, then LANG.
+func DetectLocale() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return normalizeLocale(v)
+		}
+	}
+	return FallbackLocale
+}
+
+// Message IDs used in this package.
+const (
+	MsgPersonIntro     = "person.intro"     // name, age (MsgPersonAge), city
+	MsgPersonAge       = "person.age"       // age, has plural forms
+	MsgValidationError = "validation.error" // field, value (for %T), message
+)
+
+// Messages is the catalog Person and ValidationError print from.
+// Add a locale with Messages.Set and Messages.SetPluralRule.
+var Messages = func() *Catalog {
+	c := NewCatalog()
+	c.SetPluralRule("en", OneIsSingular)
+	c.SetPluralRule("de", OneIsSingular)
+	c.SetPluralRule("fr", ZeroAndOneAreSingular)
+
+	c.Set("en", MsgPersonIntro, Message{Other: "My name is %[1]s and I am %[2]s, I live in %[3]s"})
+	c.Set("fr", MsgPersonIntro, Message{Other: "Je m'appelle %[1]s, j'ai %[2]s et j'habite à %[3]s"})
+	c.Set("de", MsgPersonIntro, Message{Other: "Ich heiße %[1]s, bin %[2]s alt und wohne in %[3]s"})
+
+	c.Set("en", MsgPersonAge, Message{One: "%d year old", Other: "%d years old"})
+	c.Set("fr", MsgPersonAge, Message{One: "%d an", Other: "%d ans"})
+	c.Set("de", MsgPersonAge, Message{One: "%d Jahr", Other: "%d Jahre"})
+
+	c.Set("en", MsgValidationError, Message{Other: "Error occurred in field %[1]s the Type is %[2]T, Full error: %[3]v"})
+	c.Set("fr", MsgValidationError, Message{Other: "Erreur dans le champ %[1]s de type %[2]T : %[3]v"})
+	c.Set("de", MsgValidationError, Message{Other: "Fehler im Feld %[1]s vom Typ %[2]T: %[3]v"})
+	return c
+}()
+
+var defaultPrinter atomic.Pointer[Printer]
+
+func init() {
+	defaultPrinter.Store(Messages.Printer(DetectLocale()))
+}
+
+// SetLocale changes the locale String methods print in, instead of the one
+// from the environment.
+func SetLocale(locale string) {
+	defaultPrinter.Store(Messages.Printer(locale))
+}
+
+// DefaultPrinter is the Printer String methods use.
+func DefaultPrinter() *Printer {
+	return defaultPrinter.Load()
+}
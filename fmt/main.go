@@ -1,53 +1,132 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
 )
 
 type Person struct {
-	Name string
-	Age  int
-	City string
+	Name string `validate:"required"`
+	Age  int    `validate:"min=0,max=150"`
+	City string `validate:"required"`
 }
 
 // TODO: Implement String() method for Person
 // Should return formatted string with all fields
 
+// String is in the language of the environment (LANG), see i18n.go.
 func (p Person) String() string {
-	return fmt.Sprintf("My name is %s and I am %d years old, I live in %v", p.Name, p.Age, p.City)
+	return p.Localized(DefaultPrinter())
 }
 
+// Localized is String in the language of pr.
+func (p Person) Localized(pr *Printer) string {
+	age := pr.Plural(MsgPersonAge, p.Age, p.Age)
+	return pr.Sprintf(MsgPersonIntro, p.Name, age, p.City)
+}
+
+// ValidationError is one field that failed validation.
 type ValidationError struct {
-	Field        string
+	Field        string // path to the field, e.g. "address.city" or "tags[2]"
 	ErrorMessage string
+	Code         string // machine-readable reason, one of the Code constants
+	Value        any    // the value that was rejected
 }
 
 // TODO: Implement Error() method
 // Should return properly formatted error message
 
-func (v ValidationError) Error() error {
-	return fmt.Errorf("Error occurred in field %s the Type is %T, Full error is %v", v.Field, v.Field, v.ErrorMessage)
+// Error is the field and the message, "Age: must be at most 150", or just
+// the message when there is no field.
+func (v ValidationError) Error() string {
+	if v.Field == "" {
+		return v.ErrorMessage
+	}
+	return fmt.Sprintf("%s: %s", v.Field, v.ErrorMessage)
 }
 
+// String describes the error with the type of the rejected Value, in the
+// language of the environment (LANG).
 func (v ValidationError) String() string {
-	return fmt.Sprintf("Error occurred in field %s the Type is %T, Full error: %v", v.Field, v.Field, v.ErrorMessage)
+	return v.Localized(DefaultPrinter())
 }
 
-// func quiz() {
-// 	var dayOfWeek string
-// 	// TODO: Ask user questions using fmt.Print
-// 	fmt.Print("What day is it?: ")
+// Localized is String in the language of pr.
+func (v ValidationError) Localized(pr *Printer) string {
+	return pr.Sprintf(MsgValidationError, v.Field, v.Value, v.ErrorMessage)
+}
 
-// 	// Read answers using fmt.Scan
-// 	fmt.Scan(&dayOfWeek)
+// quiz asks a few questions and prints the answers, try:
+// go run . -quiz
+// printf 'Wednesday\nAda Lovelace\n36\nn\nLondon\n1815-12-10\n' | go run . -quiz
+func quiz(in io.Reader, out io.Writer) error {
+	// TODO: Ask user questions using fmt.Print
+	// Read answers using fmt.Scan
+	// Both are done by a Prompter now, see prompt.go
+	p := NewPrompter(in, out)
+	if f, ok := in.(*os.File); ok {
+		p.Echo = !isTerminal(f.Fd())
+	}
+	p.MaxAttempts = 3
+
+	days := []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+	if _, err := Ask(p, Choice("What day is it?", days...).WithDefault(time.Now().Weekday().String())); err != nil {
+		return err
+	}
+
+	var person Person
+	var err error
+	if person.Name, err = Ask(p, Text("What is your name?")); err != nil {
+		return err
+	}
+	age := Number("How old are you?").WithValidator(func(n int) error {
+		if n < 0 || n > 150 {
+			return errors.New("that's not a real age, try 0 to 150")
+		}
+		return nil
+	})
+	if person.Age, err = Ask(p, age); err != nil {
+		return err
+	}
+	lagos, err := Ask(p, YesNo("Do you live in Lagos?").WithDefault(true))
+	if err != nil {
+		return err
+	}
+	person.City = "Lagos"
+	if !lagos {
+		if person.City, err = Ask(p, Text("Where do you live then?")); err != nil {
+			return err
+		}
+	}
+	if _, err := Ask(p, Date("When is your birthday?", "")); err != nil {
+		return err
+	}
 
-// 	// Format and display results
-// 	fmt.Println(dayOfWeek)
-// }
+	// Format and display results
+	fmt.Fprintf(out, "\n%s\n", person)
+	return p.Summary().Render(out, TextRenderer{Border: BorderRounded})
+}
 
 type TableData struct {
 	Headers []string
 	Rows    [][]string
+	Columns []Column
+	Footer  [][]string // summary rows such as totals, drawn below the body
+
+	// plainFooter marks Footer rows that skip the columns' NumberFormat,
+	// a count of 6 rows shouldn't show up as "₦6.00".
+	plainFooter []bool
+
+	// subtotalRows marks the Rows GroupBy added, which paging doesn't count,
+	// and plainRows the ones among them that skip the NumberFormat like
+	// plainFooter. Sort and Filter drop both, they run before GroupBy.
+	subtotalRows []bool
+	plainRows    []bool
 }
 
 // TODO: Implement function to print formatted table
@@ -77,33 +156,31 @@ type TableData struct {
 	E.g fmt.Printf("|%.3f|", 3.1423455666) // |3.142|
 */
 
-func (t TableData) Print() {
-
-	// fmt.Printf("|%-10s", t.Headers[0])
-	// fmt.Printf("|%-10s", t.Headers[1])
-	// fmt.Printf("|%-10s", t.Headers[2])
-	// fmt.Printf("|%-10s\n", t.Headers[3])
+// The widths used to be a hard-coded %-30s, now every column is as wide as
+// its widest cell. We can't use "%-*s" for the padding because fmt counts
+// runes, and emoji or CJK text takes up more cells than it has runes.
 
-	for i := range t.Headers {
-		if i == len(t.Headers)-1 {
-			fmt.Printf("|%-30s\n", t.Headers[i])
-			break
-		}
-		fmt.Printf("|%-30s", t.Headers[i])
+// Print writes the table to stdout, to write it anywhere else or in another
+// format (Markdown, CSV, TSV, HTML, JSON) use Render with a Renderer.
+// Long cells are cut to make the table fit the terminal.
+func (t TableData) Print() {
+	if err := t.Render(os.Stdout, TextRenderer{Width: TerminalWidth(os.Stdout)}); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing table: %v\n", err)
 	}
+}
+
+func main() {
+	tableOptions := registerTableFlags(flag.CommandLine)
+	askQuiz := flag.Bool("quiz", false, "ask the quiz questions on stdin instead of printing the table")
+	flag.Parse()
 
-	for i := range t.Rows {
-		for j := range t.Rows[i] {
-			if j == len(t.Rows[i])-1 {
-				fmt.Printf("|%-30s\n", t.Rows[i][j])
-				continue
-			}
-			fmt.Printf("|%-30s", t.Rows[i][j])
+	if *askQuiz {
+		if err := quiz(os.Stdin, os.Stdout); err != nil {
+			log.Fatalf("Quiz stopped: %v\n", err)
 		}
+		return
 	}
-}
 
-func main() {
 	// var name string
 	// fmt.Print("Enter your name: ")
 	// fmt.Scanln(&name)
@@ -178,19 +255,66 @@ func main() {
 	// sopuu := Person{Name: "Sopuluchukwu Nnacheta", Age: 28, City: "Lagos"}
 	// shazzar := Person{Name: "Daniel Oguejiofor", Age: 25, City: "Ibadan"}
 
-	fmt.Println(me)
+	// The samples go to stderr, stdout is only the table so that
+	// -format json or csv can be piped or saved and read back with -input
+	fmt.Fprintln(os.Stderr, me)
 	// fmt.Println(sopuu.String())
 	// fmt.Println(shazzar.String())
 
+	// Person implements fmt.Formatter, so every verb can print it differently
+	// fmt.Printf("%v\n%+v\n%#v\n%q\n", me, me, me, me)
+	// fmt.Printf("|%-30.20s|\n", me)
+
+	// The %s sentence in French, or run with LANG=de_DE.UTF-8 for German
+	// SetLocale("fr")
+	// fmt.Printf("%s\n", me)
+	// fmt.Println(Messages.Printer("de").Plural(MsgPersonAge, 1, 1))
+
+	// And read back with fmt.Sscan, from the %v form or key=value pairs
+	// var ada Person
+	// fmt.Sscan(`Name="Ada Lovelace";Age=36;City=London`, &ada)
+	// fmt.Sscan("Daniel Okoronkwo (25, Lagos)", &ada)
+
+	// Values picked by name instead of by counting Printf arguments
+	// sentence, err := Format("{Name} is {Age:%03d} and lives in {City:upper}", me)
+	// fmt.Println(sentence) // Daniel Okoronkwo is 025 and lives in LAGOS
+
 	//TODO: Use the implemented Error method
 
-	var stringError = ValidationError{Field: "String", ErrorMessage: "Could not validate string"}
-	// floatError := ValidationError{"Float", "Could not validate float"}
+	var stringError = ValidationError{Field: "String", ErrorMessage: "Could not validate string", Code: CodeInvalid, Value: ""}
+	// floatError := ValidationError{Field: "Float", ErrorMessage: "Could not validate float", Code: CodeInvalid, Value: 3.14}
 
-	fmt.Println(stringError)
+	fmt.Fprintln(os.Stderr, stringError)
 	// fmt.Println(floatError.Error())
+	// fmt.Println(stringError.String())
+
+	// Every failing field at once, errors.Is and errors.As look inside
+	// var errs ValidationErrors
+	// errs.Add(stringError)
+	// errs.Add(ValidationError{Field: "address.city", ErrorMessage: "is required", Code: CodeRequired})
+	// if err := errs.Err(); err != nil {
+	// 	fmt.Println(err, errors.Is(err, ErrRequired))
+	// }
 
-	// quiz()
+	// Checking the validate tags instead of every field by hand
+	// validator := NewValidator()
+	// validator.Register("lagos", func(v reflect.Value, _ string) error {
+	// 	if v.String() != "Lagos" {
+	// 		return errors.New("must be Lagos")
+	// 	}
+	// 	return nil
+	// })
+	// err := validator.Validate(Person{Age: 200, City: "Enugu"})
+	// fmt.Println(err) // Name: is required; Age: must be at most 150
+
+	// The same failure as an RFC 7807 problem+json body, what an HTTP
+	// handler would send with WriteProblem(w, problem)
+	// problem := ValidationProblem(err)
+	// EncodeProblem(os.Stdout, problem)
+
+	// Everything behind a wrapped error, one error per line, or as JSON for a log
+	// WriteErrorTree(os.Stdout, fmt.Errorf("loading people: %w", err))
+	// WriteErrorTreeJSON(os.Stdout, err)
 
 	// fmt.Printf("|%10v|\n", "Daniel")
 	// fmt.Printf("|%-10v|\n", "Daniel")
@@ -208,6 +332,69 @@ func main() {
 			{"Elias Emmanuel", "30", "Abuja", "3,000,000"},
 			{"Promise Nnacheta", "35", "Lagos", "3,500,000"},
 		},
+		Columns: []Column{
+			3: {Number: &NumberFormat{Precision: 2, Grouping: true, Prefix: "₦"}},
+		},
 	}
-	tableData.Print()
+	// tableData.Print()
+
+	// Prints the table with the -sort, -filter, -page, -format ... flags applied, try:
+	// go run . -sort "City,-Account Balance" -filter "Age >= 26" -border rounded
+	// go run . -format markdown > table.md && go run . -input table.md -sort -Age
+	tableData, err := tableOptions.load(tableData)
+	if err != nil {
+		log.Fatalf("Error reading table: %v\n", err)
+	}
+	if err := tableOptions.render(os.Stdout, tableData); err != nil {
+		log.Fatalf("Error rendering table: %v\n", err)
+	}
+
+	// Sorted by the real balance, not the formatted text
+	// tableData.SortBy("Account Balance", true).Print()
+	// tableData.Where("City = Lagos")
+	// tableData.Paginate(1, 2)
+
+	// Totals under the table and a subtotal after every city
+	// tableData.WithTotals(Sum, Average).Print()
+	// byCity, _ := tableData.Sort(SortKey{Column: "City"})
+	// byCity, _ = byCity.GroupBy("City", Sum)
+	// byCity.Print()
+
+	// A table straight from a slice of structs, one column per field
+	// people, _ := NewTableFromStructs([]Person{me, {Name: "Sopuluchukwu Nnacheta", Age: 28, City: "Lagos"}})
+	// people.Print()
+
+	// The same table with a proper frame
+	// tableData.Render(os.Stdout, TextRenderer{Border: BorderRounded})
+	// tableData.Render(os.Stdout, TextRenderer{Border: BorderASCII, RowSeparators: true})
+
+	// Squeezed into 50 columns, long names wrap onto a second line
+	// tableData.Render(os.Stdout, TextRenderer{Border: BorderLight, Width: 50, Overflow: OverflowWrap})
+
+	// Bold headers and striped rows, with the City column in cyan (try NO_COLOR=1)
+	// theme := *DefaultTheme
+	// theme.Columns = map[string]string{"City": ANSICyan}
+	// tableData.Render(os.Stdout, TextRenderer{Border: BorderLight, Theme: &theme})
+
+	// Rows that arrive one at a time, e.g. from a log file being tailed
+	// stream := NewStreamWriter(os.Stdout, tableData.Headers, StreamOptions{Lookahead: 2, HeaderEvery: 3, Border: BorderASCII})
+	// for _, row := range tableData.Rows {
+	// 	stream.WriteRow(row...)
+	// }
+	// stream.Close()
+
+	// And back again from text, e.g. a table pasted from a README
+	// fromDocs, err := ReadMarkdown(strings.NewReader("| Name | Age |\n| --- | --: |\n| Ada | 36 |\n"))
+	// fromCSV, err := ReadCSV(strings.NewReader("Name,Age\n\"Okoronkwo, Daniel\",25\n"))
+
+	// The same table in the other formats, any io.Writer works
+	// tableData.Render(os.Stdout, MarkdownRenderer{})
+	// tableData.Render(os.Stdout, CSVRenderer{})
+	// tableData.Render(os.Stdout, TSVRenderer{})
+	// tableData.Render(os.Stdout, HTMLRenderer{})
+	// tableData.Render(os.Stdout, JSONRenderer{Indent: "  "})
+
+	// Or the TableData value itself, like %#v but one field per line
+	// PrettyPrinter{MaxLen: 3}.Fprint(os.Stdout, tableData)
+	// fmt.Printf("%v\n", Pretty(me))
 }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// NumberFormat says how a numeric column is displayed.
+// The cells keep their raw value, e.g. "1000000" or "1,000,000", and are only
+// formatted when the table is rendered, so sorting still sees the real number.
+type NumberFormat struct {
+	Precision int    // digits after the decimal point
+	Grouping  bool   // add thousands separators: 1000000 -> 1,000,000
+	Prefix    string // currency prefix such as "₦" or "$"
+}
+
+// Format turns v into text, e.g. -1234.5 with {2, true, "$"} is "-$1,234.50".
+func (nf NumberFormat) Format(v float64) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+
+	digits := strconv.FormatFloat(v, 'f', max(nf.Precision, 0), 64)
+	if nf.Grouping {
+		whole, frac, hasFrac := strings.Cut(digits, ".")
+		digits = groupThousands(whole)
+		if hasFrac {
+			digits += "." + frac
+		}
+	}
+	return sign + nf.Prefix + digits
+}
+
+// groupThousands puts a comma between every group of three digits,
+// counting from the right.
+func groupThousands(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+// ParseNumber reads the kind of numbers people put in tables: "25", "-3.5",
+// "1,000,000" and "₦1,500,000.00". Currency symbols in front are ignored and
+// commas are only accepted between groups of three digits.
+func ParseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+
+	sign := ""
+	if s[0] == '-' || s[0] == '+' {
+		sign, s = s[:1], s[1:]
+	}
+	s = strings.TrimLeftFunc(s, func(r rune) bool { return unicode.Is(unicode.Sc, r) })
+	// "-$5" and "$-5" are both seen in the wild
+	if sign == "" && s != "" && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+
+	if strings.Contains(s, ",") {
+		whole, frac, hasFrac := strings.Cut(s, ".")
+		groups := strings.Split(whole, ",")
+		if len(groups[0]) == 0 || len(groups[0]) > 3 {
+			return 0, false
+		}
+		for _, g := range groups[1:] {
+			if len(g) != 3 {
+				return 0, false
+			}
+		}
+		s = strings.Join(groups, "")
+		if hasFrac {
+			s += "." + frac
+		}
+	}
+
+	// ParseFloat also accepts "Inf", "NaN" and hex floats, which nobody means
+	// in a table cell, so only plain digits and one dot get through.
+	if s == "" || strings.Trim(s, "0123456789.") != "" || strings.Count(s, ".") > 1 {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(sign+s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Reading Tables Back
+
+// The renderers turn a TableData into text, the readers here go the other
+// way, so a table copied out of a README, exported from a spreadsheet or
+// printed by Print can be sorted, filtered and rendered again.
+// The first row of the text is always taken as the headers.
+
+// ParseError reports where in the input a table could not be read.
+// Line and Column are 1-based, Column counts bytes like encoding/csv does.
+type ParseError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ErrNoTable is returned when the input doesn't contain a table at all.
+var ErrNoTable = errors.New("no table found")
+
+// ReadCSV reads a table from CSV, the format CSVRenderer writes.
+// Quoted fields may contain commas, quotes ("") and newlines.
+// Rows don't have to have one cell per header, see TableData.Repair.
+func ReadCSV(r io.Reader) (TableData, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		var csvErr *csv.ParseError
+		if errors.As(err, &csvErr) {
+			return TableData{}, &ParseError{Line: csvErr.Line, Column: csvErr.Column, Msg: csvErr.Err.Error()}
+		}
+		return TableData{}, err
+	}
+	if len(records) == 0 {
+		return TableData{}, ErrNoTable
+	}
+	return TableData{Headers: records[0], Rows: records[1:]}, nil
+}
+
+// ReadMarkdown reads the first GitHub Flavored Markdown table in r, so r can
+// be a whole document. A table is a header row followed by a delimiter row
+// like "| --- | ---: |", and ends at the first line that isn't a row.
+// "\|" is a pipe inside a cell and "<br>" a line break, as MarkdownRenderer
+// writes them. The ':' in the delimiter row set the column alignments.
+func ReadMarkdown(r io.Reader) (TableData, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return TableData{}, err
+	}
+
+	start := -1
+	for i := 0; i+1 < len(lines); i++ {
+		if strings.Contains(lines[i], "|") && isDelimiterRow(lines[i+1]) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return TableData{}, ErrNoTable
+	}
+
+	unescape := strings.NewReplacer(`\|`, "|", "<br>", "\n", "<br/>", "\n", "<br />", "\n")
+	row := func(n int) []string {
+		cells := splitRow(lines[n])
+		out := make([]string, len(cells))
+		for i, c := range cells {
+			out[i] = unescape.Replace(c.text)
+		}
+		return out
+	}
+
+	t := TableData{Headers: row(start)}
+	delimiter := splitRow(lines[start+1])
+	if len(delimiter) != len(t.Headers) {
+		return TableData{}, &ParseError{
+			Line:   start + 2,
+			Column: 1,
+			Msg:    fmt.Sprintf("delimiter row has %d cells, the header has %d", len(delimiter), len(t.Headers)),
+		}
+	}
+	columns := make([]Column, len(delimiter))
+	aligned := false
+	for i, c := range delimiter {
+		align, ok := parseDelimiter(c.text)
+		if !ok {
+			return TableData{}, &ParseError{Line: start + 2, Column: c.column, Msg: fmt.Sprintf("bad delimiter %q, expected ---, :---, ---: or :---:", c.text)}
+		}
+		columns[i].Align = align
+		aligned = aligned || align != AlignAuto
+	}
+	if aligned {
+		t.Columns = columns
+	}
+
+	for n := start + 2; n < len(lines) && strings.Contains(lines[n], "|"); n++ {
+		t.Rows = append(t.Rows, row(n))
+	}
+	return t, nil
+}
+
+// isDelimiterRow reports whether line looks like "|---|:--:|". One good cell
+// is enough, so that a typo in the others is reported instead of missed.
+func isDelimiterRow(line string) bool {
+	if !strings.Contains(line, "|") {
+		return false
+	}
+	for _, c := range splitRow(line) {
+		if _, ok := parseDelimiter(c.text); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDelimiter reads one cell of a Markdown delimiter row.
+func parseDelimiter(s string) (Alignment, bool) {
+	left, right := strings.HasPrefix(s, ":"), strings.HasSuffix(s, ":")
+	dashes := strings.TrimSuffix(strings.TrimPrefix(s, ":"), ":")
+	if dashes == "" || strings.Trim(dashes, "-") != "" {
+		return AlignAuto, false
+	}
+	switch {
+	case left && right:
+		return AlignCenter, true
+	case right:
+		return AlignRight, true
+	case left:
+		return AlignLeft, true
+	}
+	return AlignAuto, true
+}
+
+// textSeparators are the vertical lines of the border styles in border.go.
+var textSeparators = []string{"|", "│", "┃", "║"}
+
+// ReadText reads a table drawn by Print or a TextRenderer: "|Name |Age"
+// lines, or any of the boxed styles like BorderASCII or BorderLight.
+// Rule lines ("+---+", "├───┤") are skipped and the padding around each cell
+// is trimmed. A cell in quotes like "a | b" keeps the pipe in it, and "" in
+// there is a quote, which is how TextRenderer draws such cells.
+// The rows after the last rule inside the body are taken as the Footer,
+// that's how TextRenderer draws totals. When there is a rule between every
+// two rows they are RowSeparators instead, and there's no telling a footer of
+// one row apart, so it all goes in Rows.
+// The table ends at the first line that is neither a row nor a rule.
+//
+// The text can't say which columns were numbers or how they were formatted,
+// so "₦1,000.00" stays as it is, ParseNumber still understands it.
+func ReadText(r io.Reader) (TableData, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return TableData{}, err
+	}
+
+	var (
+		t          TableData
+		headerSeen bool
+		rules      []int // number of body rows seen when each rule came
+		leading    bool  // the header line starts with a separator
+		trailing   bool  // ... and ends with one
+	)
+	for n, line := range lines {
+		line = stripANSI(line) // in case it was printed with colors
+		trimmed := strings.TrimSpace(line)
+		if isRuleLine(trimmed) {
+			if headerSeen {
+				rules = append(rules, len(t.Rows))
+			}
+			continue
+		}
+		if !containsAny(trimmed, textSeparators) {
+			if headerSeen {
+				break
+			}
+			continue // text in front of the table
+		}
+
+		cells, err := splitQuoted(line, n+1)
+		if err != nil {
+			return TableData{}, err
+		}
+		if !headerSeen {
+			leading = hasPrefixAny(trimmed, textSeparators)
+			trailing = len(cells) > 1 && hasSuffixAny(trimmed, textSeparators)
+		}
+		if leading && len(cells) > 0 {
+			cells = cells[1:]
+		}
+		if trailing && len(cells) > 0 {
+			cells = cells[:len(cells)-1]
+		}
+
+		if !headerSeen {
+			t.Headers, headerSeen = cells, true
+			continue
+		}
+		t.Rows = append(t.Rows, cells)
+	}
+	if !headerSeen {
+		return TableData{}, ErrNoTable
+	}
+
+	// The rule under the header and the one at the bottom don't count.
+	var inner []int
+	for _, at := range rules {
+		if at > 0 && at < len(t.Rows) {
+			inner = append(inner, at)
+		}
+	}
+	separated := len(inner) > 1 && len(inner) == len(t.Rows)-1
+	if len(inner) > 0 && !separated {
+		last := inner[len(inner)-1]
+		t.Footer = t.Rows[last:]
+		t.Rows = t.Rows[:last:last]
+	}
+	return t, nil
+}
+
+// isRuleLine reports whether line is a horizontal rule: only dashes, plus
+// signs, colons, pipes and box-drawing characters, with at least one line.
+func isRuleLine(line string) bool {
+	if line == "" {
+		return false
+	}
+	fill := false
+	for _, r := range line {
+		switch {
+		case r == '-' || r == '=' || r >= 0x2500 && r <= 0x257F && !isSeparator(r):
+			fill = true
+		case r == '+' || r == ':' || r == ' ' || isSeparator(r):
+		default:
+			return false
+		}
+	}
+	return fill
+}
+
+func isSeparator(r rune) bool {
+	return r == '|' || r == '│' || r == '┃' || r == '║'
+}
+
+// cell is one cell of a row and the 1-based byte column it starts at.
+type cell struct {
+	text   string
+	column int
+}
+
+// splitRow splits a Markdown row on the pipes that aren't escaped with a
+// backslash, and trims every cell. A pipe at the start or end of the line is
+// the edge of the table, not an empty cell.
+func splitRow(line string) []cell {
+	var cells []cell
+	start := 0
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, trimCell(line, start, i))
+			start = i + 1
+		}
+	}
+	cells = append(cells, trimCell(line, start, len(line)))
+
+	trimmed := strings.TrimSpace(line)
+	if len(cells) > 1 && strings.HasPrefix(trimmed, "|") {
+		cells = cells[1:]
+	}
+	if len(cells) > 1 && strings.HasSuffix(trimmed, "|") && !strings.HasSuffix(trimmed, `\|`) {
+		cells = cells[:len(cells)-1]
+	}
+	return cells
+}
+
+func trimCell(line string, start, end int) cell {
+	s := line[start:end]
+	lead := len(s) - len(strings.TrimLeft(s, " \t"))
+	return cell{text: strings.TrimSpace(s), column: start + lead + 1}
+}
+
+// splitQuoted splits a line of text output on the box separators. A cell
+// whose text starts with a quote runs to the closing quote, so separators
+// inside it are kept. n is the line number for errors.
+func splitQuoted(line string, n int) ([]string, error) {
+	var cells []string
+	var b strings.Builder
+	i := 0
+	for {
+		// skip the padding in front of the cell
+		for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		b.Reset()
+		if i < len(line) && line[i] == '"' {
+			open := i
+			for i++; ; i++ {
+				if i >= len(line) {
+					return nil, &ParseError{Line: n, Column: open + 1, Msg: "quoted cell is never closed"}
+				}
+				if line[i] != '"' {
+					b.WriteByte(line[i])
+					continue
+				}
+				if i+1 < len(line) && line[i+1] == '"' {
+					b.WriteByte('"')
+					i++
+					continue
+				}
+				i++
+				break
+			}
+			rest := i
+			for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+				i++
+			}
+			if i < len(line) && sepAt(line, i, textSeparators) == "" {
+				r, _ := utf8.DecodeRuneInString(line[i:])
+				return nil, &ParseError{Line: n, Column: rest + 1, Msg: fmt.Sprintf("unexpected %q after a quoted cell", r)}
+			}
+			cells = append(cells, b.String())
+		} else {
+			start := i
+			for i < len(line) && sepAt(line, i, textSeparators) == "" {
+				i++
+			}
+			cells = append(cells, strings.TrimRight(line[start:i], " \t"))
+		}
+		if i >= len(line) {
+			return cells, nil
+		}
+		i += len(sepAt(line, i, textSeparators))
+	}
+}
+
+// sepAt returns the separator that starts at line[i], or "".
+func sepAt(line string, i int, seps []string) string {
+	for _, sep := range seps {
+		if strings.HasPrefix(line[i:], sep) {
+			return sep
+		}
+	}
+	return ""
+}
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPrefixAny(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSuffixAny(s string, suffixes []string) bool {
+	for _, p := range suffixes {
+		if strings.HasSuffix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// readLines reads all of r, without the line endings.
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSuffix(scanner.Text(), "\r"))
+	}
+	return lines, scanner.Err()
+}
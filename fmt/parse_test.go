@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+// Whatever TextRenderer draws, ReadText reads back the same table.
+func TestReadTextRoundTrip(t *testing.T) {
+	table := TableData{
+		Headers: []string{"Name", "Note", "Age"},
+		Rows: [][]string{
+			{"Daniel Okoronkwo", `"Quoted" thing`, "25"},
+			{"Sopuluchukwu Nnacheta", "a | b", "28"},
+			{"Promise Nnacheta", `say "hi" │ ┃ ║`, "35"},
+		},
+	}.WithTotals(Sum, Maximum)
+
+	for name, border := range BorderStyles {
+		if border == BorderNone {
+			continue // its columns are only spaces apart, ReadText doesn't read it
+		}
+		var buf bytes.Buffer
+		if err := table.Render(&buf, TextRenderer{Border: border}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadText(&buf)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+
+		rows, footer := table.Rows, table.Footer
+		if border.MarkAlignment {
+			// Markdown has no footer, the totals come back as rows
+			rows, footer = append(slices.Clone(rows), footer...), nil
+		}
+		if !slices.Equal(got.Headers, table.Headers) {
+			t.Errorf("%s: Headers = %q, want %q", name, got.Headers, table.Headers)
+		}
+		if !slices.EqualFunc(got.Rows, rows, slices.Equal) {
+			t.Errorf("%s: Rows = %q, want %q", name, got.Rows, rows)
+		}
+		if !slices.EqualFunc(got.Footer, footer, slices.Equal) {
+			t.Errorf("%s: Footer = %q, want %q", name, got.Footer, footer)
+		}
+	}
+}
+
+// Rules between the rows are row separators, not a footer.
+func TestReadTextRowSeparators(t *testing.T) {
+	table := TableData{
+		Headers: []string{"Name", "Age"},
+		Rows:    [][]string{{"Ada", "36"}, {"Grace", "45"}, {"Linus", "28"}},
+	}
+	var buf bytes.Buffer
+	if err := table.Render(&buf, TextRenderer{Border: BorderASCII, RowSeparators: true}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadText(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.EqualFunc(got.Rows, table.Rows, slices.Equal) || got.Footer != nil {
+		t.Errorf("Rows = %q, Footer = %q, want %q and no footer", got.Rows, got.Footer, table.Rows)
+	}
+}
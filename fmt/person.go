@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Formatter
+
+// String is all fmt needs to print a Person, but then every verb prints the
+// same sentence. A type that implements fmt.Formatter gets the verb and the
+// flags, width and precision (the fmt.State) and decides for itself:
+//
+//	%v   Daniel Okoronkwo (25, Lagos)
+//	%+v  {Name:Daniel Okoronkwo Age:25 City:Lagos}
+//	%#v  main.Person{Name:"Daniel Okoronkwo", Age:25, City:"Lagos"}
+//	%s   My name is Daniel Okoronkwo and I am 25 years old, I live in Lagos
+//	%q   "Daniel Okoronkwo (25, Lagos)"
+//	%x   44616e69656c... (the %v text in hex)
+//
+// Width and precision work like they do for strings: %-40v pads on the
+// right, %.10s keeps the first 10 characters.
+
+// Format implements fmt.Formatter.
+func (p Person) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case f.Flag('#'):
+			pad(f, p.goString())
+		case f.Flag('+'):
+			pad(f, fmt.Sprintf("{Name:%s Age:%d City:%s}", p.Name, p.Age, p.City))
+		default:
+			pad(f, p.compact())
+		}
+	case 's':
+		pad(f, p.String())
+	case 'q', 'x', 'X':
+		// fmt already knows how to quote and hex a string, with every flag,
+		// FormatString turns the state back into a verb like "%-#20q".
+		fmt.Fprintf(f, fmt.FormatString(f, verb), p.compact())
+	default:
+		// the same thing fmt prints for a verb that doesn't fit the type
+		fmt.Fprintf(f, "%%!%c(%T=%s)", verb, p, p.compact())
+	}
+}
+
+// compact is the %v form, which Scan reads back.
+func (p Person) compact() string {
+	return fmt.Sprintf("%s (%d, %s)", p.Name, p.Age, p.City)
+}
+
+func (p Person) goString() string {
+	return fmt.Sprintf("%T{Name:%q, Age:%d, City:%q}", p, p.Name, p.Age, p.City)
+}
+
+// pad writes s cut to the precision and padded to the width in f, both
+// counted in characters like fmt does for %s.
+func pad(f fmt.State, s string) {
+	if prec, ok := f.Precision(); ok && utf8.RuneCountInString(s) > prec {
+		s = string([]rune(s)[:prec])
+	}
+	width, ok := f.Width()
+	if n := utf8.RuneCountInString(s); ok && n < width {
+		padding := strings.Repeat(" ", width-n)
+		if f.Flag('-') {
+			s += padding
+		} else {
+			s = padding + s
+		}
+	}
+	fmt.Fprint(f, s)
+}
+
+// Scanner
+
+// Scan implements fmt.Scanner, so fmt.Sscan and fmt.Fscan can read a Person
+// back in either of these forms:
+//
+//	Daniel Okoronkwo (25, Lagos)             the %v form
+//	Name="Daniel Okoronkwo";Age=25;City=Lagos
+//
+// Names and cities may be quoted, which a key=value name with a space in it
+// has to be. The keys can come in any order and in any case, a key that is
+// left out leaves its field as it was. Mistakes are a ValidationError for
+// the field, with the character offset in the message.
+// When there is nothing left to read, fmt.Fscan returns io.ErrUnexpectedEOF.
+func (p *Person) Scan(state fmt.ScanState, verb rune) error {
+	if verb != 'v' && verb != 's' {
+		return fmt.Errorf("Person.Scan: can't scan with %%%c", verb)
+	}
+	state.SkipSpace()
+	s := &personScanner{state: state}
+	r, ok := s.peek()
+	if !ok {
+		return io.EOF
+	}
+
+	// A quoted name can only be the %v form, otherwise what comes first,
+	// "(" or "=", says which form it is.
+	if r == '"' {
+		name, err := s.quoted("Name")
+		if err != nil {
+			return err
+		}
+		return s.compact(p, name)
+	}
+	text := s.readUntil(func(r rune) bool { return r == '(' || r == '=' || r == '\n' })
+	if r, _ := s.peek(); r == '=' {
+		return s.keyValues(p, strings.TrimSpace(text))
+	}
+	return s.compact(p, strings.TrimSpace(text))
+}
+
+// personScanner reads a Person one rune at a time and keeps count of where
+// it is, for the error messages.
+type personScanner struct {
+	state  fmt.ScanState
+	offset int
+}
+
+func (s *personScanner) next() (rune, bool) {
+	r, _, err := s.state.ReadRune()
+	if err != nil {
+		return 0, false
+	}
+	s.offset++
+	return r, true
+}
+
+func (s *personScanner) peek() (rune, bool) {
+	r, ok := s.next()
+	if ok {
+		s.state.UnreadRune()
+		s.offset--
+	}
+	return r, ok
+}
+
+// readUntil reads runes up to, not including, the first one stop is true for.
+func (s *personScanner) readUntil(stop func(rune) bool) string {
+	var b strings.Builder
+	for {
+		r, ok := s.peek()
+		if !ok || stop(r) {
+			return b.String()
+		}
+		s.next()
+		b.WriteRune(r)
+	}
+}
+
+func (s *personScanner) skipSpaces() {
+	s.readUntil(func(r rune) bool { return r == '\n' || !unicode.IsSpace(r) })
+}
+
+// fail is a ValidationError for field at the current offset.
+func (s *personScanner) fail(field string, value any, format string, args ...any) error {
+	return ValidationError{
+		Field:        field,
+		ErrorMessage: fmt.Sprintf("at character %d: ", s.offset+1) + fmt.Sprintf(format, args...),
+		Code:         CodeInvalid,
+		Value:        value,
+	}
+}
+
+// expect reads r, or fails for field.
+func (s *personScanner) expect(field string, want rune) error {
+	s.skipSpaces()
+	r, ok := s.peek()
+	if !ok {
+		return s.fail(field, nil, "expected %q, got the end of the input", want)
+	}
+	if r != want {
+		return s.fail(field, string(r), "expected %q, got %q", want, r)
+	}
+	s.next()
+	return nil
+}
+
+// quoted reads a Go-style double-quoted string.
+func (s *personScanner) quoted(field string) (string, error) {
+	start := s.offset
+	s.next() // the opening quote
+	raw := []rune{'"'}
+	for escaped := false; ; {
+		r, ok := s.next()
+		if !ok || r == '\n' {
+			s.offset = start
+			return "", s.fail(field, string(raw), "quote is never closed")
+		}
+		raw = append(raw, r)
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			value, err := strconv.Unquote(string(raw))
+			if err != nil {
+				s.offset = start
+				return "", s.fail(field, string(raw), "bad quoted string: %v", err)
+			}
+			return value, nil
+		}
+	}
+}
+
+// value reads a quoted string, or text up to the first rune stop is true for.
+func (s *personScanner) value(field string, stop func(rune) bool) (string, error) {
+	if r, _ := s.peek(); r == '"' {
+		return s.quoted(field)
+	}
+	return strings.TrimSpace(s.readUntil(stop)), nil
+}
+
+func (s *personScanner) age(text string) (int, error) {
+	age, err := strconv.Atoi(text)
+	if err != nil {
+		s.offset -= utf8.RuneCountInString(text)
+		return 0, s.fail("Age", text, "%q is not a whole number", text)
+	}
+	return age, nil
+}
+
+// compact reads the rest of "Name (Age, City)" after the name.
+func (s *personScanner) compact(p *Person, name string) error {
+	if name == "" {
+		return s.fail("Name", "", "name is missing")
+	}
+	if err := s.expect("Age", '('); err != nil {
+		return err
+	}
+	s.skipSpaces()
+	age, err := s.age(strings.TrimSpace(s.readUntil(func(r rune) bool { return r == ',' || r == ')' || r == '\n' })))
+	if err != nil {
+		return err
+	}
+	if err := s.expect("City", ','); err != nil {
+		return err
+	}
+	s.skipSpaces()
+	city, err := s.value("City", func(r rune) bool { return r == ')' || r == '\n' })
+	if err != nil {
+		return err
+	}
+	if err := s.expect("City", ')'); err != nil {
+		return err
+	}
+	p.Name, p.Age, p.City = name, age, city
+	return nil
+}
+
+// keyValues reads "Name=...;Age=...;City=..." after the first key.
+// A value without quotes ends at ";" or a space, and so does the Person.
+func (s *personScanner) keyValues(p *Person, key string) error {
+	result := *p
+	endOfValue := func(r rune) bool { return r == ';' || unicode.IsSpace(r) }
+	for {
+		s.next() // the "="
+		field := map[string]string{"name": "Name", "age": "Age", "city": "City"}[strings.ToLower(key)]
+		if field == "" {
+			s.offset -= utf8.RuneCountInString(key) + 1
+			return s.fail(key, key, "unknown field %q, expected Name, Age or City", key)
+		}
+		text, err := s.value(field, endOfValue)
+		if err != nil {
+			return err
+		}
+		switch field {
+		case "Name":
+			result.Name = text
+		case "City":
+			result.City = text
+		case "Age":
+			if result.Age, err = s.age(text); err != nil {
+				return err
+			}
+		}
+
+		if r, ok := s.peek(); !ok || r != ';' {
+			break
+		}
+		s.next()
+		key = s.readUntil(func(r rune) bool { return r == '=' || endOfValue(r) })
+		if r, ok := s.peek(); !ok || r != '=' {
+			return s.fail(key, key, "expected \"=\" after %q", key)
+		}
+	}
+	*p = result
+	return nil
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Named Placeholders
+
+// Printf arguments are matched to verbs by position, which is easy to get
+// wrong once a format has more than a few of them. Format looks values up by
+// name instead:
+//
+//	Format("{Name} is {Age:%03d} and lives in {City:upper}", person)
+//	// Daniel Okoronkwo is 025 and lives in LAGOS
+//
+// A placeholder is a path, then optionally a fmt verb and filters, all
+// separated by ':'. The path is a struct field (by Go or json name), a map
+// key, a slice index or a method without arguments such as String or Error,
+// with dots in between to go deeper: {Address.City}, {Rows.0.1}.
+// "{.}" is the value itself. The verb defaults to %v, so a Stringer or
+// Formatter prints the way it always does. A path that ends on a nil, a
+// field of a nil embedded struct included, prints it as the verb does: <nil>.
+//
+// The filters work on the formatted text, in the order they are written:
+//
+//	upper, lower  change the case
+//	truncate=N    cut to N cells, with "…" as the last one
+//	pad=N         pad to N cells on the left, pad=-N on the right, like %Ns and %-Ns
+//
+// "{{" and "}}" are a literal brace.
+
+var (
+	// ErrUnknownField is returned for a path that leads nowhere.
+	ErrUnknownField = errors.New("unknown field")
+	// ErrBadPlaceholder is returned for a template that can't be read.
+	ErrBadPlaceholder = errors.New("bad placeholder")
+)
+
+// PlaceholderError reports which placeholder failed and where it is.
+// Line and Column are 1-based, Column counts characters.
+type PlaceholderError struct {
+	Line        int
+	Column      int
+	Placeholder string // as written, braces included
+	Err         error
+}
+
+func (e *PlaceholderError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s: %v", e.Line, e.Column, e.Placeholder, e.Err)
+}
+
+func (e *PlaceholderError) Unwrap() error {
+	return e.Err
+}
+
+// Format fills in the placeholders of template from data. It stops at the
+// first placeholder it can't fill and returns a *PlaceholderError.
+func Format(template string, data any) (string, error) {
+	var b strings.Builder
+	line, column := 1, 1
+	for i := 0; i < len(template); {
+		fail := func(placeholder string, err error) (string, error) {
+			return "", &PlaceholderError{Line: line, Column: column, Placeholder: placeholder, Err: err}
+		}
+
+		c := template[i]
+		switch {
+		case (c == '{' || c == '}') && i+1 < len(template) && template[i+1] == c:
+			b.WriteByte(c)
+			i += 2
+			column += 2
+			continue
+		case c == '}':
+			return fail("}", fmt.Errorf("%w: single '}', write '}}' for a brace", ErrBadPlaceholder))
+		case c == '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				return fail(template[i:], fmt.Errorf("%w: '{' is never closed", ErrBadPlaceholder))
+			}
+			placeholder := template[i : i+end+1]
+			s, err := fillPlaceholder(placeholder[1:end], data)
+			if err != nil {
+				return fail(placeholder, err)
+			}
+			b.WriteString(s)
+			i += end + 1
+			column += utf8.RuneCountInString(placeholder)
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(template[i:])
+		b.WriteRune(r)
+		i += size
+		column++
+		if r == '\n' {
+			line, column = line+1, 1
+		}
+	}
+	return b.String(), nil
+}
+
+// fillPlaceholder formats the placeholder text between the braces.
+func fillPlaceholder(text string, data any) (string, error) {
+	path, spec, _ := strings.Cut(text, ":")
+	path = strings.TrimSpace(path)
+
+	verb := "%v"
+	var filters []string
+	if spec != "" {
+		filters = strings.Split(spec, ":")
+		if strings.HasPrefix(filters[0], "%") {
+			verb, filters = filters[0], filters[1:]
+			if strings.Count(verb, "%") != 1 {
+				return "", fmt.Errorf("%w: %q is not a single verb", ErrBadPlaceholder, verb)
+			}
+		}
+	}
+
+	v, err := lookupPath(reflect.ValueOf(data), path)
+	if err != nil {
+		return "", err
+	}
+	var s string
+	if v.IsValid() {
+		s = fmt.Sprintf(verb, v.Interface())
+	} else {
+		s = fmt.Sprintf(verb, nil)
+	}
+
+	for _, filter := range filters {
+		if s, err = applyFilter(s, strings.TrimSpace(filter)); err != nil {
+			return "", err
+		}
+	}
+	return s, nil
+}
+
+// lookupPath follows a dotted path from v. An invalid Value is a nil that
+// the path ended on.
+func lookupPath(v reflect.Value, path string) (reflect.Value, error) {
+	if path == "" || path == "." {
+		return v, nil
+	}
+	walked := ""
+	for _, name := range strings.Split(path, ".") {
+		if !indirect(v).IsValid() {
+			return reflect.Value{}, fmt.Errorf("%w %q: %s is nil", ErrUnknownField, name, orData(walked))
+		}
+		next, ok := lookupName(v, name)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%w %q in %s", ErrUnknownField, name, indirect(v).Type())
+		}
+		v = next
+		walked = joinPath(walked, name)
+	}
+	return v, nil
+}
+
+func orData(path string) string {
+	if path == "" {
+		return "the data"
+	}
+	return path
+}
+
+// lookupName finds one step of a path in v: a method, a field, a map key or
+// an index, tried in that order.
+func lookupName(v reflect.Value, name string) (reflect.Value, bool) {
+	if m, ok := method(v, name); ok {
+		return m, true
+	}
+	v = indirect(v)
+	if m, ok := method(v, name); ok {
+		return m, true
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if f, ok := v.Type().FieldByName(name); ok && f.IsExported() {
+			field, err := v.FieldByIndexErr(f.Index)
+			if err != nil {
+				// promoted through a nil embedded pointer, so the field is nil too
+				return reflect.Value{}, true
+			}
+			return field, true
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Type().Field(i); f.IsExported() && fieldName(f) == name {
+				return v.Field(i), true
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+		value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		return value, value.IsValid()
+	case reflect.Slice, reflect.Array, reflect.String:
+		i, err := strconv.Atoi(name)
+		if err != nil || i < 0 || i >= v.Len() {
+			return reflect.Value{}, false
+		}
+		return v.Index(i), true
+	}
+	return reflect.Value{}, false
+}
+
+// method calls the method name of v when it takes no arguments and returns
+// one value, like String and Error do.
+func method(v reflect.Value, name string) (reflect.Value, bool) {
+	if !v.IsValid() {
+		return reflect.Value{}, false
+	}
+	m := v.MethodByName(name)
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return reflect.Value{}, false
+	}
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return reflect.Value{}, false
+	}
+	return m.Call(nil)[0], true
+}
+
+// applyFilter runs one filter on s.
+func applyFilter(s, filter string) (string, error) {
+	name, arg, hasArg := strings.Cut(filter, "=")
+	switch name {
+	case "upper":
+		return strings.ToUpper(s), nil
+	case "lower":
+		return strings.ToLower(s), nil
+	case "truncate", "pad":
+		n, err := strconv.Atoi(arg)
+		if !hasArg || err != nil {
+			return "", fmt.Errorf("%w: %s needs a number, e.g. %s=10", ErrBadPlaceholder, name, name)
+		}
+		if name == "truncate" {
+			return truncateEllipsis(s, n), nil
+		}
+		if n < 0 {
+			return padRight(s, -n), nil
+		}
+		return alignCell(s, n, AlignRight), nil
+	}
+	return "", fmt.Errorf("%w: unknown filter %q", ErrBadPlaceholder, name)
+}
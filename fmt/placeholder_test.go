@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+type address struct {
+	City string
+}
+
+type resident struct {
+	Name string
+	*address
+}
+
+// A field promoted from a nil embedded pointer is nil, not a panic.
+func TestFormatNilEmbedded(t *testing.T) {
+	tests := []struct {
+		template string
+		data     any
+		want     string
+		err      error
+	}{
+		{"{Name} in {City}", resident{Name: "Ada", address: &address{City: "Lagos"}}, "Ada in Lagos", nil},
+		{"{Name} in {City}", resident{Name: "Ada"}, "Ada in <nil>", nil},
+		{"{City:upper}", &resident{Name: "Ada"}, "<NIL>", nil},
+		{"{City.Len}", resident{Name: "Ada"}, "", ErrUnknownField},
+	}
+	for _, tt := range tests {
+		got, err := Format(tt.template, tt.data)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("Format(%q) = %q, %v, want %q, %v", tt.template, got, err, tt.want, tt.err)
+		}
+		var pe *PlaceholderError
+		if tt.err != nil && !errors.As(err, &pe) {
+			t.Errorf("Format(%q) error is %T, want *PlaceholderError", tt.template, err)
+		}
+	}
+}
@@ -0,0 +1,474 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Pretty Printing
+
+// %+v prints a TableData on one line, which is fine for a Person but not for
+// anything nested. A PrettyPrinter writes Go values the way %#v does, but
+// with one field or element per line:
+//
+//	main.TableData{
+//	  Headers: []string{"Name", "Age"},
+//	  Rows: [][]string{
+//	    {"Daniel", "25"},
+//	  },
+//	  Columns: []main.Column(nil),
+//	  ...
+//	}
+//
+// Slices and maps of plain values stay on one line while they fit in Width.
+// Map keys are sorted, so the output is the same every time. A pointer that
+// leads back to a value that is still being printed is shown as <cycle>
+// instead of being followed forever.
+
+// PrettyPrinter holds the settings for pretty printing, the zero value is
+// ready to use.
+type PrettyPrinter struct {
+	Indent   string // one level of indentation, two spaces when empty
+	MaxDepth int    // levels of nesting to show, deeper values are {…}; 0 means no limit
+	MaxLen   int    // elements of a slice or map and characters of a string to show; 0 means no limit
+	Width    int    // line width for one-line slices and maps, 80 when 0; negative keeps everything on its own line
+
+	// Methods prints values with a GoString, Error or String method (in that
+	// order) by calling it, instead of showing their fields.
+	Methods bool
+
+	Color  ColorMode
+	Colors map[reflect.Kind]string // SGR codes by kind, DefaultPrettyColors when nil
+}
+
+// DefaultPrettyColors colors strings, numbers and booleans, and dims nil and
+// the markers for cut off values. Every number kind uses the reflect.Int entry,
+// nil and the markers the reflect.Invalid one.
+var DefaultPrettyColors = map[reflect.Kind]string{
+	reflect.String:  ANSIGreen,
+	reflect.Int:     ANSICyan,
+	reflect.Bool:    ANSIYellow,
+	reflect.Invalid: ANSIGray,
+}
+
+// Fprint pretty prints v to w, followed by a newline.
+func (pp PrettyPrinter) Fprint(w io.Writer, v any) error {
+	s := pp.newState(pp.Color.enabled(w))
+	s.value(reflect.ValueOf(v), 0, false)
+	s.b.WriteByte('\n')
+	_, err := io.WriteString(w, s.b.String())
+	return err
+}
+
+// Sprint returns v pretty printed, without color.
+func (pp PrettyPrinter) Sprint(v any) string {
+	s := pp.newState(false)
+	s.value(reflect.ValueOf(v), 0, false)
+	return s.b.String()
+}
+
+// Pretty wraps v for the fmt functions: fmt.Printf("%v\n", Pretty(table)).
+func Pretty(v any) fmt.Formatter {
+	return PrettyPrinter{}.Value(v)
+}
+
+// Value wraps v for the fmt functions, printed with pp's settings.
+// %+v calls methods like Methods does, a width sets Width: %100v.
+// fmt doesn't say where the text goes, so ColorAuto can't tell whether it
+// is a terminal and never colors; set Color to ColorAlways for that.
+func (pp PrettyPrinter) Value(v any) fmt.Formatter {
+	return prettyValue{pp: pp, v: v}
+}
+
+type prettyValue struct {
+	pp PrettyPrinter
+	v  any
+}
+
+// Format implements fmt.Formatter.
+func (p prettyValue) Format(f fmt.State, verb rune) {
+	if verb != 'v' && verb != 's' {
+		fmt.Fprintf(f, "%%!%c(%T)", verb, p.v)
+		return
+	}
+	pp := p.pp
+	if f.Flag('+') {
+		pp.Methods = true
+	}
+	if width, ok := f.Width(); ok {
+		pp.Width = width
+	}
+	s := pp.newState(pp.Color == ColorAlways)
+	s.value(reflect.ValueOf(p.v), 0, false)
+	fmt.Fprint(f, s.b.String())
+}
+
+// prettyState is one run of a PrettyPrinter.
+type prettyState struct {
+	pp     PrettyPrinter
+	b      strings.Builder
+	colors map[reflect.Kind]string // nil without color
+	path   map[prettyVisit]bool    // pointers, maps and slices being printed
+}
+
+type prettyVisit struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+func (pp PrettyPrinter) newState(color bool) *prettyState {
+	if pp.Indent == "" {
+		pp.Indent = "  "
+	}
+	if pp.Width == 0 {
+		pp.Width = 80
+	}
+	s := &prettyState{pp: pp, path: map[prettyVisit]bool{}}
+	if color {
+		s.colors = pp.Colors
+		if s.colors == nil {
+			s.colors = DefaultPrettyColors
+		}
+	}
+	return s
+}
+
+func (s *prettyState) paint(text string, kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		kind = reflect.Int
+	}
+	return paint(text, s.colors[kind])
+}
+
+func (s *prettyState) newline(depth int) {
+	s.b.WriteByte('\n')
+	s.b.WriteString(strings.Repeat(s.pp.Indent, depth))
+}
+
+// value writes v at nesting depth. elided leaves out the type name of a
+// struct, slice or map, as Go does for the elements of a composite literal.
+func (s *prettyState) value(v reflect.Value, depth int, elided bool) {
+	if text, ok := s.scalar(v); ok {
+		s.b.WriteString(text)
+		return
+	}
+
+	typeName := ""
+	if !elided {
+		typeName = v.Type().String()
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		s.value(v.Elem(), depth, false)
+	case reflect.Pointer:
+		if s.enter(v, 0) {
+			s.b.WriteString(s.paint("<cycle "+v.Type().String()+">", reflect.Invalid))
+			return
+		}
+		defer s.leave(v, 0)
+		if !elided {
+			// elements drop the & along with the type, {...} instead of &T{...}
+			s.b.WriteByte('&')
+		}
+		s.value(v.Elem(), depth, elided)
+	case reflect.Struct:
+		s.structValue(v, depth, typeName)
+	case reflect.Slice, reflect.Array:
+		if s.enter(v, v.Len()) {
+			s.b.WriteString(s.paint("<cycle "+v.Type().String()+">", reflect.Invalid))
+			return
+		}
+		defer s.leave(v, v.Len())
+		s.list(v, depth, typeName)
+	case reflect.Map:
+		if s.enter(v, 0) {
+			s.b.WriteString(s.paint("<cycle "+v.Type().String()+">", reflect.Invalid))
+			return
+		}
+		defer s.leave(v, 0)
+		s.mapValue(v, depth, typeName)
+	}
+}
+
+// scalar formats v when it takes a single line and has nothing to indent:
+// nil, booleans, numbers, strings, funcs and channels, and values printed
+// by their methods.
+func (s *prettyState) scalar(v reflect.Value) (string, bool) {
+	if !v.IsValid() {
+		return s.paint("nil", reflect.Invalid), true
+	}
+	if text, ok := s.method(v); ok {
+		return text, true
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return s.paint(strconv.FormatBool(v.Bool()), reflect.Bool), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return s.paint(strconv.FormatInt(v.Int(), 10), v.Kind()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return s.paint(strconv.FormatUint(v.Uint(), 10), v.Kind()), true
+	case reflect.Float32, reflect.Float64:
+		return s.paint(strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), v.Kind()), true
+	case reflect.Complex64, reflect.Complex128:
+		return s.paint(strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits()), v.Kind()), true
+	case reflect.String:
+		text := v.String()
+		more := ""
+		if s.pp.MaxLen > 0 && utf8.RuneCountInString(text) > s.pp.MaxLen {
+			text = string([]rune(text)[:s.pp.MaxLen])
+			more = s.paint(ellipsis, reflect.Invalid)
+		}
+		return s.paint(strconv.Quote(text), reflect.String) + more, true
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if v.IsNil() {
+			return fmt.Sprintf("(%s)(%s)", v.Type(), s.paint("nil", reflect.Invalid)), true
+		}
+		return fmt.Sprintf("(%s)(%#x)", v.Type(), v.Pointer()), true
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return s.paint("nil", reflect.Invalid), true
+		}
+	case reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return v.Type().String() + "(" + s.paint("nil", reflect.Invalid) + ")", true
+		}
+	}
+	return "", false
+}
+
+// method calls the GoString, Error or String method of v when Methods is set.
+// Values from unexported fields can't be called, they are printed as usual,
+// and so are nil pointers, also inside an interface. A method that panics
+// is shown the way fmt shows it, %!v(PANIC=String method: ...).
+func (s *prettyState) method(v reflect.Value) (text string, ok bool) {
+	if !s.pp.Methods || !v.CanInterface() || !indirect(v).IsValid() {
+		return "", false
+	}
+	name := ""
+	defer func() {
+		if r := recover(); r != nil {
+			text, ok = s.paint(fmt.Sprintf("%%!v(PANIC=%s method: %v)", name, r), reflect.Invalid), true
+		}
+	}()
+	switch x := v.Interface().(type) {
+	case fmt.GoStringer:
+		name = "GoString"
+		return x.GoString(), true
+	case error:
+		name = "Error"
+		return x.Error(), true
+	case fmt.Stringer:
+		name = "String"
+		return x.String(), true
+	}
+	return "", false
+}
+
+// enter marks v as being printed, and reports whether it already was.
+func (s *prettyState) enter(v reflect.Value, n int) bool {
+	if v.Kind() == reflect.Array {
+		return false
+	}
+	key := prettyVisit{typ: v.Type(), ptr: v.Pointer(), len: n}
+	if s.path[key] {
+		return true
+	}
+	s.path[key] = true
+	return false
+}
+
+func (s *prettyState) leave(v reflect.Value, n int) {
+	if v.Kind() != reflect.Array {
+		delete(s.path, prettyVisit{typ: v.Type(), ptr: v.Pointer(), len: n})
+	}
+}
+
+// tooDeep writes "{…}" when depth is past MaxDepth.
+func (s *prettyState) tooDeep(depth int) bool {
+	if s.pp.MaxDepth > 0 && depth >= s.pp.MaxDepth {
+		s.b.WriteString("{" + s.paint(ellipsis, reflect.Invalid) + "}")
+		return true
+	}
+	return false
+}
+
+func (s *prettyState) structValue(v reflect.Value, depth int, typeName string) {
+	s.b.WriteString(typeName)
+	if v.NumField() == 0 {
+		s.b.WriteString("{}")
+		return
+	}
+	if s.tooDeep(depth) {
+		return
+	}
+	s.b.WriteByte('{')
+	for i := 0; i < v.NumField(); i++ {
+		s.newline(depth + 1)
+		s.b.WriteString(v.Type().Field(i).Name + ": ")
+		s.value(v.Field(i), depth+1, false)
+		s.b.WriteByte(',')
+	}
+	s.newline(depth)
+	s.b.WriteByte('}')
+}
+
+// shown is how many of n elements fit in MaxLen.
+func (s *prettyState) shown(n int) int {
+	if s.pp.MaxLen > 0 && n > s.pp.MaxLen {
+		return s.pp.MaxLen
+	}
+	return n
+}
+
+func (s *prettyState) more(n int) string {
+	return s.paint(fmt.Sprintf("%s %d more", ellipsis, n), reflect.Invalid)
+}
+
+func (s *prettyState) list(v reflect.Value, depth int, typeName string) {
+	s.b.WriteString(typeName)
+	if v.Len() == 0 {
+		s.b.WriteString("{}")
+		return
+	}
+	if s.tooDeep(depth) {
+		return
+	}
+	n := s.shown(v.Len())
+	elided := v.Type().Elem().Kind() != reflect.Interface
+
+	var items []string
+	for i := 0; i < n; i++ {
+		items = append(items, s.inline(v.Index(i)))
+	}
+	if s.fits(typeName, items, depth) {
+		if n < v.Len() {
+			items = append(items, s.more(v.Len()-n))
+		}
+		s.b.WriteString("{" + strings.Join(items, ", ") + "}")
+		return
+	}
+
+	s.b.WriteByte('{')
+	for i := 0; i < n; i++ {
+		s.newline(depth + 1)
+		s.value(v.Index(i), depth+1, elided)
+		s.b.WriteByte(',')
+	}
+	if n < v.Len() {
+		s.newline(depth + 1)
+		s.b.WriteString(s.more(v.Len() - n))
+	}
+	s.newline(depth)
+	s.b.WriteByte('}')
+}
+
+func (s *prettyState) mapValue(v reflect.Value, depth int, typeName string) {
+	s.b.WriteString(typeName)
+	if v.Len() == 0 {
+		s.b.WriteString("{}")
+		return
+	}
+	if s.tooDeep(depth) {
+		return
+	}
+	keys := v.MapKeys()
+	slices.SortFunc(keys, compareKeys)
+	n := s.shown(len(keys))
+	elided := v.Type().Elem().Kind() != reflect.Interface
+
+	var items []string
+	for _, k := range keys[:n] {
+		key, value := s.inline(k), s.inline(v.MapIndex(k))
+		if key == "" || value == "" {
+			items = nil
+			break
+		}
+		items = append(items, key+": "+value)
+	}
+	if s.fits(typeName, items, depth) {
+		if n < len(keys) {
+			items = append(items, s.more(len(keys)-n))
+		}
+		s.b.WriteString("{" + strings.Join(items, ", ") + "}")
+		return
+	}
+
+	s.b.WriteByte('{')
+	for _, k := range keys[:n] {
+		s.newline(depth + 1)
+		if key := s.inline(k); key != "" {
+			s.b.WriteString(key)
+		} else {
+			s.b.WriteString(fmt.Sprintf("%#v", k))
+		}
+		s.b.WriteString(": ")
+		s.value(v.MapIndex(k), depth+1, elided)
+		s.b.WriteByte(',')
+	}
+	if n < len(keys) {
+		s.newline(depth + 1)
+		s.b.WriteString(s.more(len(keys) - n))
+	}
+	s.newline(depth)
+	s.b.WriteByte('}')
+}
+
+// inline is the scalar form of v, or "" when v needs more than one line.
+func (s *prettyState) inline(v reflect.Value) string {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	text, ok := s.scalar(v)
+	if !ok || strings.Contains(text, "\n") {
+		return ""
+	}
+	return text
+}
+
+// fits reports whether items can go on one line after the type name at depth.
+func (s *prettyState) fits(typeName string, items []string, depth int) bool {
+	if s.pp.Width < 0 || len(items) == 0 || slices.Contains(items, "") {
+		return false
+	}
+	width := len(s.pp.Indent)*depth + len(typeName) + 2
+	for _, item := range items {
+		width += displayWidth(item) + 2
+	}
+	return width <= s.pp.Width
+}
+
+// compareKeys orders map keys: numbers and strings by value, false before
+// true, anything else by how %v prints it.
+func compareKeys(a, b reflect.Value) int {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+	if a.IsValid() && b.IsValid() && a.Kind() == b.Kind() {
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(a.Int(), b.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(a.Uint(), b.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(a.Float(), b.Float())
+		case reflect.String:
+			return cmp.Compare(a.String(), b.String())
+		case reflect.Bool:
+			return cmp.Compare(strconv.FormatBool(a.Bool()), strconv.FormatBool(b.Bool()))
+		}
+	}
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Problem Details
+
+// RFC 7807 is a standard JSON body for HTTP errors, sent with the content
+// type application/problem+json. Validation failures list the fields that
+// failed in an "invalid-params" member, which is the RFC's own example:
+//
+//	{
+//	  "type": "about:blank",
+//	  "title": "Unprocessable Entity",
+//	  "status": 422,
+//	  "detail": "2 fields failed validation",
+//	  "invalid-params": [
+//	    {"name": "Name", "reason": "is required", "code": "required"},
+//	    {"name": "Age", "reason": "must be at most 150", "code": "max"}
+//	  ]
+//	}
+
+// ProblemContentType is the media type of a problem details document.
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetails is an RFC 7807 problem details document. It is an error
+// too, so a client can return what the server sent, and errors.Is and
+// errors.As see the ValidationErrors in it.
+type ProblemDetails struct {
+	Type          string         `json:"type,omitempty"` // a URI for the kind of problem, "about:blank" for plain HTTP errors
+	Title         string         `json:"title,omitempty"`
+	Status        int            `json:"status,omitempty"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"` // a URI for this occurrence
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam is one entry of "invalid-params". Code is not in the RFC,
+// it carries ValidationError.Code so clients don't have to parse the reason.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	Code   string `json:"code,omitempty"`
+}
+
+// ValidationProblem turns a validation error into a 422 problem listing
+// every ValidationError in it, also from inside wrapped and joined errors.
+// The offending values are left out, they may be passwords or other things
+// not to echo back. Other errors become a 400 problem with the error text as
+// the detail, and a nil error is no problem: the zero ProblemDetails.
+func ValidationProblem(err error) ProblemDetails {
+	if err == nil {
+		return ProblemDetails{}
+	}
+	p := ProblemDetails{Type: "about:blank"}
+
+	errs := validationErrorsIn(err)
+	if len(errs) == 0 {
+		p.Status = http.StatusBadRequest
+		p.Title = http.StatusText(p.Status)
+		p.Detail = err.Error()
+		return p
+	}
+
+	p.Status = http.StatusUnprocessableEntity
+	p.Title = http.StatusText(p.Status)
+	if len(errs) == 1 {
+		p.Detail = "1 field failed validation"
+	} else {
+		p.Detail = fmt.Sprintf("%d fields failed validation", len(errs))
+	}
+	for _, e := range errs {
+		p.InvalidParams = append(p.InvalidParams, InvalidParam{Name: e.Field, Reason: e.ErrorMessage, Code: e.Code})
+	}
+	return p
+}
+
+// ValidationErrors turns "invalid-params" back into ValidationErrors,
+// entries without a code get CodeInvalid.
+func (p ProblemDetails) ValidationErrors() ValidationErrors {
+	var errs ValidationErrors
+	for _, param := range p.InvalidParams {
+		code := param.Code
+		if code == "" {
+			code = CodeInvalid
+		}
+		errs = append(errs, ValidationError{Field: param.Name, ErrorMessage: param.Reason, Code: code})
+	}
+	return errs
+}
+
+func (p ProblemDetails) Error() string {
+	var parts []string
+	for _, s := range []string{p.Title, p.Detail} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if errs := p.ValidationErrors(); len(errs) > 0 {
+		parts = append(parts, errs.Error())
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("problem with status %d", p.Status)
+	}
+	return strings.Join(parts, ": ")
+}
+
+// Unwrap returns the invalid params as ValidationErrors, or nil.
+func (p ProblemDetails) Unwrap() error {
+	return p.ValidationErrors().Err()
+}
+
+// EncodeProblem writes p to w as JSON.
+func EncodeProblem(w io.Writer, p ProblemDetails) error {
+	data, err := marshalJSON(p)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteProblem sends p as the response, with its Status (500 when it has
+// none) and the problem+json content type.
+func WriteProblem(w http.ResponseWriter, p ProblemDetails) error {
+	status := p.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	return EncodeProblem(w, p)
+}
+
+// DecodeProblem reads a problem details document from r.
+func DecodeProblem(r io.Reader) (ProblemDetails, error) {
+	var p ProblemDetails
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return ProblemDetails{}, fmt.Errorf("decoding problem details: %w", err)
+	}
+	return p, nil
+}
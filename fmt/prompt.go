@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Prompts
+
+// fmt.Scan reads one word and leaves everything else in the buffer, so a
+// typo means the next question gets the rest of the line. A Prompter reads
+// whole lines, turns them into the type the question wants, and asks again
+// with the reason when that doesn't work:
+//
+//	How old are you? [25]: twenty
+//	  "twenty" is not a whole number
+//	How old are you? [25]: 26
+//
+// It only needs an io.Reader and an io.Writer, so a quiz can be answered
+// from a file or a strings.Reader as well as from a terminal.
+
+// ErrTooManyAttempts is returned by Ask when every attempt was wrong.
+var ErrTooManyAttempts = errors.New("too many invalid answers")
+
+// Prompter asks questions on out and reads the answers from in.
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	// MaxAttempts is how many answers Ask reads before giving up, 0 means
+	// it keeps asking.
+	MaxAttempts int
+
+	// Echo writes each answer after its prompt. Turn it on when the input
+	// is a file, nobody typed the answer so the output would lack it.
+	Echo bool
+
+	asked [][]string // question and answer, for Summary
+}
+
+// NewPrompter returns a Prompter that reads from in and writes to out.
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out}
+}
+
+// Question asks for a value of type T. Make one with Text, Number, YesNo,
+// Choice or Date and adjust it with WithDefault and WithValidator.
+type Question[T any] struct {
+	Prompt string
+
+	parse      func(string) (T, error)
+	format     func(T) string
+	hint       string // shown after the prompt, e.g. "[y/n]"
+	options    []string
+	def        T
+	hasDefault bool
+	validators []func(T) error
+}
+
+// WithDefault returns q with an answer to use when the line is left empty.
+func (q Question[T]) WithDefault(v T) Question[T] {
+	q.def, q.hasDefault = v, true
+	return q
+}
+
+// WithValidator returns q with one more check on the answer. An error is
+// shown to the user and the question is asked again.
+func (q Question[T]) WithValidator(fn func(T) error) Question[T] {
+	q.validators = append(q.validators[:len(q.validators):len(q.validators)], fn)
+	return q
+}
+
+// Text asks for a line of text.
+func Text(prompt string) Question[string] {
+	return Question[string]{
+		Prompt: prompt,
+		parse:  func(s string) (string, error) { return s, nil },
+		format: func(s string) string { return s },
+	}
+}
+
+// Number asks for a whole number.
+func Number(prompt string) Question[int] {
+	return Question[int]{
+		Prompt: prompt,
+		parse: func(s string) (int, error) {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return 0, fmt.Errorf("%q is not a whole number", s)
+			}
+			return n, nil
+		},
+		format: strconv.Itoa,
+	}
+}
+
+// YesNo asks a yes or no question, "y", "yes", "n" and "no" in any case.
+func YesNo(prompt string) Question[bool] {
+	return Question[bool]{
+		Prompt: prompt,
+		hint:   "[y/n]",
+		parse: func(s string) (bool, error) {
+			switch strings.ToLower(s) {
+			case "y", "yes", "true":
+				return true, nil
+			case "n", "no", "false":
+				return false, nil
+			}
+			return false, fmt.Errorf("%q is not yes or no", s)
+		},
+		format: func(b bool) string {
+			if b {
+				return "yes"
+			}
+			return "no"
+		},
+	}
+}
+
+// Choice asks to pick one of options, by its number in the list, its name
+// in any case, or the start of a name as long as only one option starts so.
+func Choice(prompt string, options ...string) Question[string] {
+	return Question[string]{
+		Prompt:  prompt,
+		options: options,
+		hint:    fmt.Sprintf("(1-%d)", len(options)),
+		parse: func(s string) (string, error) {
+			if n, err := strconv.Atoi(s); err == nil {
+				if n < 1 || n > len(options) {
+					return "", fmt.Errorf("pick a number from 1 to %d", len(options))
+				}
+				return options[n-1], nil
+			}
+			var matches []string
+			for _, o := range options {
+				if strings.EqualFold(o, s) {
+					return o, nil
+				}
+				if len(s) <= len(o) && strings.EqualFold(o[:len(s)], s) {
+					matches = append(matches, o)
+				}
+			}
+			switch len(matches) {
+			case 0:
+				return "", fmt.Errorf("%q is not one of the options", s)
+			case 1:
+				return matches[0], nil
+			}
+			return "", fmt.Errorf("%q could be %s", s, strings.Join(matches, " or "))
+		},
+		format: func(s string) string { return s },
+	}
+}
+
+// Date asks for a date written like layout, a time package layout such as
+// "2006-01-02" (the default when layout is empty).
+func Date(prompt, layout string) Question[time.Time] {
+	if layout == "" {
+		layout = time.DateOnly
+	}
+	return Question[time.Time]{
+		Prompt: prompt,
+		hint:   "(" + layout + ")",
+		parse: func(s string) (time.Time, error) {
+			t, err := time.Parse(layout, s)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("%q is not a date like %s", s, layout)
+			}
+			return t, nil
+		},
+		format: func(t time.Time) string { return t.Format(layout) },
+	}
+}
+
+// Ask asks q until it gets a valid answer. An empty line is the default,
+// or asked again when q has none. It fails with ErrTooManyAttempts after
+// p.MaxAttempts wrong answers, and with io.ErrUnexpectedEOF when the input
+// runs out first.
+//
+// Ask is a function rather than a method because methods can't have type
+// parameters of their own.
+func Ask[T any](p *Prompter, q Question[T]) (T, error) {
+	var zero T
+	for i, option := range q.options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, option)
+	}
+
+	prompt := q.Prompt
+	if q.hint != "" {
+		prompt += " " + q.hint
+	}
+	if q.hasDefault {
+		prompt += " [" + q.format(q.def) + "]"
+	}
+
+	for attempt := 1; ; attempt++ {
+		if _, err := fmt.Fprintf(p.out, "%s: ", prompt); err != nil {
+			return zero, err
+		}
+		line, err := p.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return zero, err
+		}
+		line = strings.TrimSpace(line)
+		if p.Echo {
+			fmt.Fprintln(p.out, line)
+		}
+
+		answer, problem := q.check(line)
+		if problem == nil {
+			p.asked = append(p.asked, []string{q.Prompt, q.format(answer)})
+			return answer, nil
+		}
+		fmt.Fprintf(p.out, "  %v\n", problem)
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return zero, fmt.Errorf("%s: %w", q.Prompt, ErrTooManyAttempts)
+		}
+	}
+}
+
+// check turns one line into an answer, or says what is wrong with it.
+func (q Question[T]) check(line string) (T, error) {
+	if line == "" {
+		if q.hasDefault {
+			return q.def, nil
+		}
+		var zero T
+		return zero, errors.New("an answer is required")
+	}
+	answer, err := q.parse(line)
+	if err != nil {
+		return answer, err
+	}
+	for _, validate := range q.validators {
+		if err := validate(answer); err != nil {
+			return answer, err
+		}
+	}
+	return answer, nil
+}
+
+// Summary is every question answered so far with its answer.
+func (p *Prompter) Summary() TableData {
+	return TableData{Headers: []string{"Question", "Answer"}, Rows: p.asked}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// lookupColumn finds a column by header. An exact match wins, otherwise the
+// match is case-insensitive so "city" finds "City" on the command line.
+func (t TableData) lookupColumn(name string) (int, error) {
+	name = strings.TrimSpace(name)
+	if i := t.columnIndex(name); i >= 0 {
+		return i, nil
+	}
+	for i, h := range t.Headers {
+		if strings.EqualFold(h, name) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("unknown column %q", name)
+}
+
+// compareCells orders two cells, by value when the column is numeric and
+// by text otherwise. Numbers sort before text that doesn't parse.
+func compareCells(a, b string, numeric bool) int {
+	if numeric {
+		x, okA := ParseNumber(a)
+		y, okB := ParseNumber(b)
+		switch {
+		case okA && okB:
+			return cmp.Compare(x, y)
+		case okA:
+			return -1
+		case okB:
+			return 1
+		}
+	}
+	return cmp.Compare(a, b)
+}
+
+// Sorting
+
+// SortKey is one column to sort by.
+type SortKey struct {
+	Column     string
+	Descending bool
+}
+
+// ParseSortKeys reads a sort spec like "City,-Age" or "City:asc,Age:desc".
+// A leading "-" or a ":desc" suffix sorts that column in descending order.
+func ParseSortKeys(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key := SortKey{Column: part}
+		if name, ok := strings.CutPrefix(part, "-"); ok {
+			key = SortKey{Column: name, Descending: true}
+		} else if name, order, ok := strings.Cut(part, ":"); ok {
+			switch strings.ToLower(order) {
+			case "asc":
+				key = SortKey{Column: name}
+			case "desc":
+				key = SortKey{Column: name, Descending: true}
+			default:
+				return nil, fmt.Errorf("sort order %q for %q is not asc or desc", order, name)
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Sort returns a copy of the table with its rows sorted by the given keys,
+// the first key first, ties broken by the next one. Numeric columns are sorted
+// by value and rows that are equal on every key keep their order.
+func (t TableData) Sort(keys ...SortKey) (TableData, error) {
+	type column struct {
+		index      int
+		numeric    bool
+		descending bool
+	}
+	columns := make([]column, len(keys))
+	for i, key := range keys {
+		index, err := t.lookupColumn(key.Column)
+		if err != nil {
+			return t, err
+		}
+		columns[i] = column{index, t.isNumeric(index), key.Descending}
+	}
+
+	t.Rows = slices.Clone(t.Rows)
+	t.subtotalRows, t.plainRows = nil, nil
+	slices.SortStableFunc(t.Rows, func(a, b []string) int {
+		for _, col := range columns {
+			c := compareCells(cellAt(a, col.index), cellAt(b, col.index), col.numeric)
+			if col.descending {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return t, nil
+}
+
+// SortBy returns a copy of the table with its rows sorted by the named column.
+// Numeric columns are sorted by value, so "1,500,000" comes after "900,000"
+// even though it is smaller as a string. Rows with equal values keep their order.
+// An unknown column leaves the table as it is.
+func (t TableData) SortBy(header string, descending bool) TableData {
+	sorted, err := t.Sort(SortKey{Column: header, Descending: descending})
+	if err != nil {
+		return t
+	}
+	return sorted
+}
+
+// Filtering
+
+// Filter returns a copy of the table with only the rows keep returns true for.
+func (t TableData) Filter(keep func(row []string) bool) TableData {
+	var rows [][]string
+	for _, row := range t.Rows {
+		if keep(row) {
+			rows = append(rows, row)
+		}
+	}
+	t.Rows = rows
+	t.subtotalRows, t.plainRows = nil, nil
+	return t
+}
+
+// filterOperators is ordered so that the two-character operators are tried
+// before their one-character prefixes.
+var filterOperators = []string{">=", "<=", "!=", "==", "!~", "=", ">", "<", "~"}
+
+// Where filters rows with a simple "column op value" expression:
+//
+//	Age >= 26
+//	City = Lagos
+//	Account Balance > 1,500,000
+//	Name ~ daniel            (contains, ignoring case; !~ is "doesn't contain")
+//
+// Comparisons are numeric when the column is numeric and the value is a number,
+// otherwise they compare text. The value may be wrapped in double quotes.
+func (t TableData) Where(expr string) (TableData, error) {
+	keep, err := t.parseFilter(expr)
+	if err != nil {
+		return t, err
+	}
+	return t.Filter(keep), nil
+}
+
+func (t TableData) parseFilter(expr string) (func(row []string) bool, error) {
+	at, op := -1, ""
+	for _, candidate := range filterOperators {
+		if i := strings.Index(expr, candidate); i > 0 && (at < 0 || i < at || (i == at && len(candidate) > len(op))) {
+			at, op = i, candidate
+		}
+	}
+	if at < 0 {
+		return nil, fmt.Errorf("filter %q: expected \"column op value\" with op one of %s", expr, strings.Join(filterOperators, " "))
+	}
+
+	col, err := t.lookupColumn(expr[:at])
+	if err != nil {
+		return nil, fmt.Errorf("filter %q: %w", expr, err)
+	}
+	value := strings.TrimSpace(expr[at+len(op):])
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		value = value[1 : len(value)-1]
+	}
+
+	if op == "~" || op == "!~" {
+		needle := strings.ToLower(value)
+		return func(row []string) bool {
+			found := strings.Contains(strings.ToLower(cellAt(row, col)), needle)
+			return found == (op == "~")
+		}, nil
+	}
+
+	_, valueIsNumber := ParseNumber(value)
+	numeric := valueIsNumber && t.isNumeric(col)
+	return func(row []string) bool {
+		c := compareCells(cellAt(row, col), value, numeric)
+		switch op {
+		case "=", "==":
+			return c == 0
+		case "!=":
+			return c != 0
+		case "<":
+			return c < 0
+		case "<=":
+			return c <= 0
+		case ">":
+			return c > 0
+		}
+		return c >= 0
+	}, nil
+}
+
+// Pagination
+
+// PageInfo describes which slice of the rows a page holds.
+type PageInfo struct {
+	From, To int // 1-based positions of the first and last row shown, 0 when empty
+	Total    int // number of rows before slicing
+}
+
+// String is the footer shown under a page, e.g. "showing 1–20 of 143".
+func (p PageInfo) String() string {
+	if p.From == 0 {
+		return fmt.Sprintf("showing 0 of %d", p.Total)
+	}
+	return fmt.Sprintf("showing %d–%d of %d", p.From, p.To, p.Total)
+}
+
+// Slice returns a copy of the table with at most limit rows, starting at
+// row offset (0-based). A limit of 0 or less means "all remaining rows".
+// Subtotal rows from GroupBy aren't counted, they stay with the last row
+// of their group.
+func (t TableData) Slice(offset, limit int) (TableData, PageInfo) {
+	total := len(t.Rows)
+	for _, subtotal := range t.subtotalRows {
+		if subtotal {
+			total--
+		}
+	}
+	start := min(max(offset, 0), total)
+	end := total
+	if limit > 0 {
+		end = min(start+limit, total)
+	}
+
+	from, to := t.rowIndex(start), t.rowIndex(end)
+	t.Rows = t.Rows[from:to:to]
+	t.subtotalRows = sliceMarks(t.subtotalRows, from, to)
+	t.plainRows = sliceMarks(t.plainRows, from, to)
+	info := PageInfo{Total: total}
+	if end > start {
+		info.From, info.To = start+1, end
+	}
+	return t, info
+}
+
+// rowIndex is the index in Rows of data row n, not counting subtotal rows,
+// or len(Rows) when there are only n data rows.
+func (t TableData) rowIndex(n int) int {
+	for i := range t.Rows {
+		if i < len(t.subtotalRows) && t.subtotalRows[i] {
+			continue
+		}
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(t.Rows)
+}
+
+// sliceMarks is marks[from:to] for marks that may be shorter than Rows.
+func sliceMarks(marks []bool, from, to int) []bool {
+	from, to = min(from, len(marks)), min(to, len(marks))
+	return marks[from:to:to]
+}
+
+// Paginate returns page number page (1-based) of perPage rows each.
+func (t TableData) Paginate(page, perPage int) (TableData, PageInfo) {
+	if perPage <= 0 {
+		return t.Slice(0, 0)
+	}
+	return t.Slice((max(page, 1)-1)*perPage, perPage)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// RowError reports a row that doesn't have one cell per header.
+// Row and Column are 0-based indexes, Error() prints them 1-based.
+type RowError struct {
+	Row    int // index into Rows
+	Column int // first missing column for a short row, first extra one for a long row
+	Got    int // cells in the row
+	Want   int // cells expected, len(Headers)
+	Header string
+}
+
+func (e RowError) Error() string {
+	if e.Got < e.Want {
+		return fmt.Sprintf("row %d, column %d (%s): missing cell, row has %d of %d cells",
+			e.Row+1, e.Column+1, e.Header, e.Got, e.Want)
+	}
+	return fmt.Sprintf("row %d, column %d: extra cell, row has %d cells but there are only %d headers",
+		e.Row+1, e.Column+1, e.Got, e.Want)
+}
+
+// Short reports whether the row has too few cells, rather than too many.
+func (e RowError) Short() bool {
+	return e.Got < e.Want
+}
+
+// RowErrors is every ragged row in a table.
+type RowErrors []RowError
+
+func (e RowErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap lets errors.As find a single RowError in the list.
+func (e RowErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Validate checks that every row has exactly one cell per header.
+// It returns nil or a RowErrors listing every row that doesn't.
+func (t TableData) Validate() error {
+	var errs RowErrors
+	for r, row := range t.Rows {
+		if err, ok := t.checkRow(r, row); !ok {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (t TableData) checkRow(r int, row []string) (RowError, bool) {
+	want := len(t.Headers)
+	if len(row) == want {
+		return RowError{}, true
+	}
+	err := RowError{Row: r, Column: min(len(row), want), Got: len(row), Want: want}
+	if err.Short() {
+		err.Header = t.Headers[err.Column]
+	}
+	return err, false
+}
+
+// RaggedMode says what Repair does with a row that has the wrong number of cells.
+type RaggedMode int
+
+const (
+	RaggedFail     RaggedMode = iota // return the RowErrors, change nothing
+	RaggedPad                        // fill short rows with Placeholder, fail on long rows
+	RaggedTruncate                   // fill short rows, cut long rows and warn about it
+)
+
+// ParseRaggedMode reads "fail", "pad" or "truncate".
+func ParseRaggedMode(s string) (RaggedMode, error) {
+	switch strings.ToLower(s) {
+	case "fail":
+		return RaggedFail, nil
+	case "pad":
+		return RaggedPad, nil
+	case "truncate":
+		return RaggedTruncate, nil
+	}
+	return RaggedFail, fmt.Errorf("unknown ragged row mode %q, expected fail, pad or truncate", s)
+}
+
+// RaggedPolicy is how to deal with rows that don't match the headers.
+type RaggedPolicy struct {
+	Mode        RaggedMode
+	Placeholder string         // what missing cells are filled with, e.g. "-" or "N/A"
+	Warn        func(RowError) // told about every cut row, log.Printf when nil
+}
+
+// ErrRaggedRows is wrapped by every error Repair returns.
+var ErrRaggedRows = errors.New("table has ragged rows")
+
+// Repair returns a copy of the table where every row has one cell per header,
+// fixed the way the policy says. Rows that the policy can't fix are returned
+// as RowErrors, wrapped with ErrRaggedRows.
+func (t TableData) Repair(policy RaggedPolicy) (TableData, error) {
+	warn := policy.Warn
+	if warn == nil {
+		warn = func(e RowError) { log.Printf("Warning: %v, dropping the extra cells", e) }
+	}
+
+	var errs RowErrors
+	rows := make([][]string, len(t.Rows))
+	for r, row := range t.Rows {
+		rows[r] = row
+		rowErr, ok := t.checkRow(r, row)
+		switch {
+		case ok:
+		case policy.Mode == RaggedFail:
+			errs = append(errs, rowErr)
+		case rowErr.Short():
+			padded := append([]string(nil), row...)
+			for len(padded) < len(t.Headers) {
+				padded = append(padded, policy.Placeholder)
+			}
+			rows[r] = padded
+		case policy.Mode == RaggedTruncate:
+			warn(rowErr)
+			rows[r] = row[:len(t.Headers):len(t.Headers)]
+		default:
+			errs = append(errs, rowErr)
+		}
+	}
+	if len(errs) > 0 {
+		return t, fmt.Errorf("%w: %w", ErrRaggedRows, errs)
+	}
+	t.Rows = rows
+	return t, nil
+}
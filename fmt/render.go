@@ -0,0 +1,483 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Renderer writes a TableData in some output format to any io.Writer
+// (stdout, a file, a bytes.Buffer, an http.ResponseWriter ...)
+type Renderer interface {
+	Render(w io.Writer, t TableData) error
+}
+
+// Render writes the table to w using r.
+func (t TableData) Render(w io.Writer, r Renderer) error {
+	return r.Render(w, t)
+}
+
+// errWriter remembers the first error from the underlying writer so that
+// renderers can write line after line and only check for an error at the end.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...any) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
+
+func (ew *errWriter) print(s string) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = io.WriteString(ew.w, s)
+}
+
+// cellAt returns row[i], or "" when the row is too short.
+func cellAt(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// headerAt returns the header for column i, or a made-up name for cells
+// that sit past the last header.
+func (t TableData) headerAt(i int) string {
+	if i < len(t.Headers) {
+		return t.Headers[i]
+	}
+	return fmt.Sprintf("Column %d", i+1)
+}
+
+// TextRenderer draws the table for a terminal or a log file. Every cell is
+// padded to the width of its column, on the side its alignment asks for,
+// and the frame around the cells is drawn with Border.
+//
+// When Width is set and the table is wider than that, the columns are shrunk
+// proportionally and the cells that no longer fit are cut or wrapped, as
+// Overflow says. Cells with "\n" in them always take up several lines.
+// With Expanded each row is drawn as a block of "Header | value" lines instead.
+// Theme colors the cells, when Color says the writer should get colors.
+// Cells that hold a "|" or start with a quote are drawn in quotes, so
+// ReadText reads the table back the way it was.
+type TextRenderer struct {
+	Border        *BorderStyle // BorderPipe (the original Print layout) when nil
+	RowSeparators bool         // draw Border.Row between body rows
+	Width         int          // width budget in cells, frame included; 0 means no limit
+	Overflow      Overflow     // what to do with cells wider than their column
+	Expanded      ExpandMode   // when to draw one record per row, see expanded.go
+	Theme         *Theme       // colors for the cells, none when nil, see ansi.go
+	Color         ColorMode    // when to use Theme, ColorAuto means only on a terminal
+}
+
+// theme returns the Theme to draw with, or nil when w shouldn't get colors.
+func (r TextRenderer) theme(w io.Writer) *Theme {
+	if r.Theme == nil || !r.Color.enabled(w) {
+		return nil
+	}
+	return r.Theme
+}
+
+func (r TextRenderer) Render(w io.Writer, t TableData) error {
+	ew := &errWriter{w: w}
+	border := r.Border
+	if border == nil {
+		border = BorderPipe
+	}
+	theme := r.theme(w)
+	if r.expand(t, border) {
+		r.renderExpanded(ew, t, border, theme)
+		return ew.err
+	}
+
+	// the theme looks at the cells as they are, everything else draws them
+	// quoted where ReadText needs that to read them back
+	shown := t.quoteCells()
+	layout := textLayout{
+		border:   border,
+		widths:   shown.fitToWidth(shown.ColumnWidths(), border, r.Width),
+		aligns:   t.alignments(),
+		overflow: r.Overflow,
+	}
+
+	n := len(layout.widths)
+	var header, footer []string
+	if theme != nil {
+		header, footer = rowCodes(n, theme.Header), rowCodes(n, theme.Footer)
+	}
+
+	layout.writeRule(ew, border.Top, false)
+	layout.writeStyledRow(ew, shown.Headers, header)
+	layout.writeRule(ew, border.Header, border.MarkAlignment)
+	for i, row := range shown.displayRows() {
+		if i > 0 && r.RowSeparators && !border.MarkAlignment {
+			layout.writeRule(ew, border.Row, false)
+		}
+		var codes []string
+		if theme != nil {
+			codes = make([]string, n)
+			for col := range codes {
+				codes[col] = theme.body(t, i, col)
+			}
+		}
+		layout.writeStyledRow(ew, row, codes)
+	}
+	if len(t.Footer) > 0 && !border.MarkAlignment {
+		// Markdown only allows one delimiter row, so there the footer
+		// just follows the body.
+		layout.writeRule(ew, border.footerRule(), false)
+	}
+	for _, row := range shown.displayFooter() {
+		layout.writeStyledRow(ew, row, footer)
+	}
+	layout.writeRule(ew, border.Bottom, false)
+	return ew.err
+}
+
+// quoteCells returns a copy of t with every cell that ReadText would misread
+// in quotes: one with a column separator in it, which would split it in two,
+// and one that starts with a quote. Quotes inside are doubled, as in CSV.
+func (t TableData) quoteCells() TableData {
+	quote := func(row []string) []string {
+		out := make([]string, len(row))
+		for i, cell := range row {
+			out[i] = cell
+			if strings.HasPrefix(cell, `"`) || containsAny(cell, textSeparators) {
+				out[i] = `"` + strings.ReplaceAll(cell, `"`, `""`) + `"`
+			}
+		}
+		return out
+	}
+	t.Headers = quote(t.Headers)
+	t.Rows = slices.Clone(t.Rows)
+	for i, row := range t.Rows {
+		t.Rows[i] = quote(row)
+	}
+	t.Footer = slices.Clone(t.Footer)
+	for i, row := range t.Footer {
+		t.Footer[i] = quote(row)
+	}
+	return t
+}
+
+// textLayout is everything needed to draw the lines of a text table once
+// the column widths are known. TextRenderer and StreamWriter share it.
+type textLayout struct {
+	border   *BorderStyle
+	widths   []int
+	aligns   []Alignment
+	overflow Overflow
+}
+
+func (l textLayout) writeRule(ew *errWriter, rule Rule, markAlignment bool) {
+	if line := l.border.rule(rule, l.widths, l.aligns, markAlignment); line != "" {
+		ew.print(line + "\n")
+	}
+}
+
+// writeRow draws one logical row, which is as many physical lines
+// as its tallest cell needs.
+func (l textLayout) writeRow(ew *errWriter, row []string) {
+	l.writeStyledRow(ew, row, nil)
+}
+
+// writeStyledRow is writeRow with SGR codes for each cell. The codes wrap the
+// padded cell, so a background color fills the whole column, not just the text.
+func (l textLayout) writeStyledRow(ew *errWriter, row []string, codes []string) {
+	lines := make([][]string, len(l.widths))
+	height := 1
+	for i := range lines {
+		lines[i] = cellLines(cellAt(row, i), l.widths[i], l.overflow)
+		height = max(height, len(lines[i]))
+	}
+
+	var b strings.Builder
+	cells := make([]string, len(l.widths))
+	for n := 0; n < height; n++ {
+		for i := range cells {
+			cells[i] = paint(fitCell(cellAt(lines[i], n), l.widths[i], l.aligns[i]), cellAt(codes, i))
+		}
+		b.WriteString(l.border.line(cells) + "\n")
+	}
+	// one write per row, so a streamed row shows up all at once
+	ew.print(b.String())
+}
+
+// MarkdownRenderer writes a GitHub Flavored Markdown table.
+// Pipes inside cells are escaped and newlines become <br> so a cell never
+// breaks the row it sits in. Column alignment goes into the delimiter row.
+// GFM has no table footer, so Footer rows come right after the body.
+type MarkdownRenderer struct{}
+
+func (MarkdownRenderer) Render(w io.Writer, t TableData) error {
+	ew := &errWriter{w: w}
+	n := t.columnCount()
+
+	escape := strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+	cells := func(row []string) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = escape.Replace(cellAt(row, i))
+		}
+		return out
+	}
+
+	header := make([]string, n)
+	for i := range header {
+		header[i] = t.headerAt(i)
+	}
+	lines := [][]string{cells(header)}
+	for _, row := range append(t.displayRows(), t.displayFooter()...) {
+		lines = append(lines, cells(row))
+	}
+	aligns := t.alignments()
+
+	// Padding isn't needed by Markdown, but it keeps the source readable.
+	widths := make([]int, n)
+	for i := range widths {
+		widths[i] = 3 // the shortest delimiter GFM accepts is "---"
+	}
+	for _, line := range lines {
+		for i, cell := range line {
+			widths[i] = max(widths[i], displayWidth(cell))
+		}
+	}
+
+	writeLine := func(cells []string) {
+		for i, cell := range cells {
+			ew.printf("| %s ", alignCell(cell, widths[i], aligns[i]))
+		}
+		ew.print("|\n")
+	}
+
+	writeLine(lines[0])
+	for i := 0; i < n; i++ {
+		ew.printf("| %s ", markdownDelimiter(widths[i], t.column(i).Align, aligns[i]))
+	}
+	ew.print("|\n")
+	for _, line := range lines[1:] {
+		writeLine(line)
+	}
+	return ew.err
+}
+
+// markdownDelimiter builds the "---" under a header. A column that was left
+// on AlignAuto and came out left-aligned gets a plain "---", as that's the
+// Markdown default anyway.
+func markdownDelimiter(width int, asked, align Alignment) string {
+	switch {
+	case align == AlignRight:
+		return strings.Repeat("-", width-1) + ":"
+	case align == AlignCenter:
+		return ":" + strings.Repeat("-", width-2) + ":"
+	case asked == AlignLeft:
+		return ":" + strings.Repeat("-", width-1)
+	}
+	return strings.Repeat("-", width)
+}
+
+// CSVRenderer writes RFC 4180 CSV with the headers as the first record.
+// Fields containing commas, quotes or newlines are quoted by encoding/csv.
+// Cells are written raw, not through their NumberFormat, so a spreadsheet
+// reads "1000000" as a number instead of "₦1,000,000.00" as text.
+// Footer rows and GroupBy subtotals are left out like in JSON: a reader of
+// the file has no way to tell them from the records.
+type CSVRenderer struct {
+	Comma   rune // field delimiter, ',' when zero
+	UseCRLF bool // end lines with \r\n as RFC 4180 says, instead of \n
+}
+
+func (c CSVRenderer) Render(w io.Writer, t TableData) error {
+	cw := csv.NewWriter(w)
+	if c.Comma != 0 {
+		cw.Comma = c.Comma
+	}
+	cw.UseCRLF = c.UseCRLF
+
+	n := t.columnCount()
+	record := make([]string, n)
+
+	for i := range record {
+		record[i] = t.headerAt(i)
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	for _, row := range t.records() {
+		for i := range record {
+			record[i] = cellAt(row, i)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// TSVRenderer writes tab-separated values.
+// TSV has no quoting, so tabs, newlines and backslashes inside a cell are
+// written as the escapes \t, \n, \r and \\ instead. Cells are written raw and
+// only the records are written, like CSV.
+type TSVRenderer struct{}
+
+func (TSVRenderer) Render(w io.Writer, t TableData) error {
+	ew := &errWriter{w: w}
+	n := t.columnCount()
+	escape := strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+	writeLine := func(cell func(i int) string) {
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				ew.print("\t")
+			}
+			ew.print(escape.Replace(cell(i)))
+		}
+		ew.print("\n")
+	}
+
+	writeLine(t.headerAt)
+	for _, row := range t.records() {
+		writeLine(func(i int) string { return cellAt(row, i) })
+	}
+	return ew.err
+}
+
+// HTMLRenderer writes an HTML <table>. Every header and cell is escaped,
+// so the output is safe to drop into a page even if the data isn't trusted.
+type HTMLRenderer struct {
+	Class string // optional class attribute for the <table> element
+}
+
+func (h HTMLRenderer) Render(w io.Writer, t TableData) error {
+	ew := &errWriter{w: w}
+	n := t.columnCount()
+
+	if h.Class != "" {
+		ew.printf("<table class=\"%s\">\n", html.EscapeString(h.Class))
+	} else {
+		ew.print("<table>\n")
+	}
+
+	styles := make([]string, n)
+	for i, align := range t.alignments() {
+		switch align {
+		case AlignRight:
+			styles[i] = ` style="text-align: right"`
+		case AlignCenter:
+			styles[i] = ` style="text-align: center"`
+		}
+	}
+
+	ew.print("  <thead>\n    <tr>\n")
+	for i := 0; i < n; i++ {
+		ew.printf("      <th%s>%s</th>\n", styles[i], html.EscapeString(t.headerAt(i)))
+	}
+	ew.print("    </tr>\n  </thead>\n")
+
+	writeRows := func(rows [][]string) {
+		for _, row := range rows {
+			ew.print("    <tr>\n")
+			for i := 0; i < n; i++ {
+				ew.printf("      <td%s>%s</td>\n", styles[i], html.EscapeString(cellAt(row, i)))
+			}
+			ew.print("    </tr>\n")
+		}
+	}
+
+	ew.print("  <tbody>\n")
+	writeRows(t.displayRows())
+	ew.print("  </tbody>\n")
+	if len(t.Footer) > 0 {
+		ew.print("  <tfoot>\n")
+		writeRows(t.displayFooter())
+		ew.print("  </tfoot>\n")
+	}
+	ew.print("</table>\n")
+	return ew.err
+}
+
+// JSONRenderer writes the rows as a JSON array of objects keyed by header,
+// e.g. [{"Name": "Daniel", "Age": "25"}].
+// Keys keep the order of the headers, which a map[string]string would lose.
+// Columns with a NumberFormat are written as JSON numbers: {"Balance": 1000000}.
+// Footer rows and GroupBy subtotals are left out, they aren't records and
+// consumers can total the data themselves.
+type JSONRenderer struct {
+	Indent string // indent each level with this, compact output when empty
+}
+
+func (j JSONRenderer) Render(w io.Writer, t TableData) error {
+	ew := &errWriter{w: w}
+	n := t.columnCount()
+
+	keys := make([][]byte, n)
+	for i := range keys {
+		key, err := marshalJSON(t.headerAt(i))
+		if err != nil {
+			return err
+		}
+		keys[i] = key
+	}
+
+	newline, indent, colon := "", "", ":"
+	if j.Indent != "" {
+		newline, indent, colon = "\n", j.Indent, ": "
+	}
+
+	rows := t.records()
+	ew.print("[")
+	for r, row := range rows {
+		if r > 0 {
+			ew.print(",")
+		}
+		ew.printf("%s%s{", newline, indent)
+		for i := 0; i < n; i++ {
+			var cell any = cellAt(row, i)
+			if t.column(i).Number != nil {
+				if v, ok := ParseNumber(cellAt(row, i)); ok {
+					cell = v
+				}
+			}
+			value, err := marshalJSON(cell)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				ew.print(",")
+			}
+			ew.printf("%s%s%s%s%s%s", newline, indent, indent, keys[i], colon, value)
+		}
+		ew.printf("%s%s}", newline, indent)
+	}
+	if len(rows) > 0 {
+		ew.print(newline)
+	}
+	ew.print("]\n")
+	return ew.err
+}
+
+// marshalJSON is json.Marshal without the HTML escaping, so "<b>" stays
+// "<b>" instead of turning into "\u003cb\u003e".
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
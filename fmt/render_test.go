@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func peopleTable() TableData {
+	return TableData{
+		Headers: []string{"Name", "Age", "City", "Account Balance"},
+		Rows: [][]string{
+			{"Daniel Okoronkwo", "25", "Lagos", "1,000,000"},
+			{"Daniel Oguejiofor", "25", "Ibadan", "2,000,000"},
+			{"Promise Nnacheta", "35", "Lagos", "3,500,000"},
+		},
+		Columns: []Column{
+			3: {Number: &NumberFormat{Precision: 2, Grouping: true, Prefix: "₦"}},
+		},
+	}
+}
+
+// The record formats write the people and nothing else, not the subtotals
+// GroupBy puts between them or the totals in the footer.
+func TestGroupByRecords(t *testing.T) {
+	grouped, err := peopleTable().GroupBy("City", Sum, Count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Daniel Okoronkwo", "Promise Nnacheta", "Daniel Oguejiofor"}
+
+	var buf bytes.Buffer
+	if err := grouped.Render(&buf, JSONRenderer{}); err != nil {
+		t.Fatal(err)
+	}
+	var records []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("JSON: %v\n%s", err, buf.String())
+	}
+	var names []string
+	for _, r := range records {
+		names = append(names, r["Name"].(string))
+	}
+	if !slices.Equal(names, want) {
+		t.Errorf("JSON names = %q, want %q", names, want)
+	}
+
+	buf.Reset()
+	if err := grouped.Render(&buf, CSVRenderer{}); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := cellsIn(rows[1:], 0); !slices.Equal(names, want) {
+		t.Errorf("CSV names = %q, want %q", names, want)
+	}
+
+	buf.Reset()
+	if err := grouped.Render(&buf, TSVRenderer{}); err != nil {
+		t.Fatal(err)
+	}
+	var tsv [][]string
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")[1:] {
+		tsv = append(tsv, strings.Split(line, "\t"))
+	}
+	if names := cellsIn(tsv, 0); !slices.Equal(names, want) {
+		t.Errorf("TSV names = %q, want %q", names, want)
+	}
+}
+
+func TestFooterRule(t *testing.T) {
+	totals := peopleTable().WithTotals(Sum)
+	for name, border := range BorderStyles {
+		var buf bytes.Buffer
+		if err := totals.Render(&buf, TextRenderer{Border: border}); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(buf.String(), "\n")
+		sum := -1
+		for i, line := range lines {
+			if strings.Contains(line, "Sum") {
+				sum = i
+			}
+		}
+		if sum < 1 {
+			t.Fatalf("%s: no Sum row in\n%s", name, buf.String())
+		}
+		above := strings.TrimSpace(lines[sum-1])
+		if border.MarkAlignment {
+			// Markdown has no footer, the row goes on the body
+			if !strings.Contains(above, "Promise") {
+				t.Errorf("%s: line above the footer is %q, want the last row", name, above)
+			}
+			continue
+		}
+		if !isRuleLine(above) {
+			t.Errorf("%s: line above the footer is %q, want a rule", name, above)
+		}
+	}
+}
+
+// cellsIn is column i of rows.
+func cellsIn(rows [][]string, i int) []string {
+	var out []string
+	for _, row := range rows {
+		out = append(out, cellAt(row, i))
+	}
+	return out
+}
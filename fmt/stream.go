@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"io"
+)
+
+// StreamOptions configures a StreamWriter.
+type StreamOptions struct {
+	// Widths are the column widths to draw with. Without a Lookahead they are
+	// fixed, with one they are the smallest the estimate may come out.
+	// Columns without a width start as wide as their header.
+	Widths []int
+
+	// Lookahead is how many rows are held back to measure the columns before
+	// anything is written. 0 means start writing straight away.
+	Lookahead int
+
+	// HeaderEvery repeats the header after every N rows, like top(1) or
+	// vmstat(8) do, so it stays on screen. 0 prints the header once.
+	HeaderEvery int
+
+	Border   *BorderStyle // BorderPipe when nil
+	Overflow Overflow     // what to do with cells wider than their column
+	Columns  []Column     // per-column alignment, number format and min/max width
+}
+
+// StreamWriter writes a table one row at a time, for row sources that never
+// end or don't fit in memory (a log being tailed, a database cursor ...).
+// Unlike TableData it can't look at every row to size the columns, so it uses
+// the widths it was given, or measures the first Lookahead rows and sticks
+// with that. Cells that turn up wider later are cut or wrapped.
+type StreamWriter struct {
+	ew      *errWriter
+	opts    StreamOptions
+	headers []string
+	pending [][]string  // rows held back while measuring
+	layout  *textLayout // nil until the widths are decided
+	table   TableData   // headers and column settings, without rows
+	written int         // body rows written since the last header
+	closed  bool
+}
+
+var errStreamClosed = errors.New("stream writer is closed")
+
+// NewStreamWriter returns a StreamWriter that writes a table with the given
+// headers to w. Nothing is written until the first row, or Flush.
+func NewStreamWriter(w io.Writer, headers []string, opts StreamOptions) *StreamWriter {
+	if opts.Border == nil {
+		opts.Border = BorderPipe
+	}
+	return &StreamWriter{
+		ew:      &errWriter{w: w},
+		opts:    opts,
+		headers: headers,
+		table:   TableData{Headers: headers, Columns: opts.Columns},
+	}
+}
+
+// WriteRow adds a row to the table. The row is written to the underlying
+// writer right away, unless the lookahead window is still filling up.
+func (s *StreamWriter) WriteRow(cells ...string) error {
+	if s.closed {
+		return errStreamClosed
+	}
+	if s.ew.err != nil {
+		return s.ew.err
+	}
+
+	row := append([]string(nil), cells...)
+	if s.layout == nil {
+		s.pending = append(s.pending, row)
+		if len(s.pending) < s.opts.Lookahead {
+			return nil
+		}
+		return s.Flush()
+	}
+	s.writeRow(row)
+	return s.ew.err
+}
+
+// Flush writes out any rows held back for the lookahead window. Widths are
+// decided from whatever has been seen so far, if that hasn't happened yet.
+func (s *StreamWriter) Flush() error {
+	if s.layout == nil {
+		s.start()
+	}
+	for _, row := range s.pending {
+		s.writeRow(row)
+	}
+	s.pending = nil
+	return s.ew.err
+}
+
+// Close flushes the table and draws its bottom border. It does not close
+// the underlying writer.
+func (s *StreamWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+	err := s.Flush()
+	s.layout.writeRule(s.ew, s.opts.Border.Bottom, false)
+	s.closed = true
+	if err != nil {
+		return err
+	}
+	return s.ew.err
+}
+
+// start decides the column widths and alignments from the hints and the
+// rows held back, then draws the top of the table.
+func (s *StreamWriter) start() {
+	// The rows seen so far are the best guess for widths and numeric columns.
+	measured := s.table
+	measured.Rows = s.pending
+
+	widths := measured.ColumnWidths()
+	for i, hint := range s.opts.Widths {
+		if i >= len(widths) {
+			widths = append(widths, 0)
+		}
+		if s.opts.Lookahead > 0 {
+			widths[i] = max(widths[i], hint)
+		} else if hint > 0 {
+			widths[i] = hint
+		}
+	}
+
+	aligns := measured.alignments()
+	for len(aligns) < len(widths) {
+		aligns = append(aligns, AlignLeft)
+	}
+
+	s.layout = &textLayout{
+		border:   s.opts.Border,
+		widths:   widths,
+		aligns:   aligns,
+		overflow: s.opts.Overflow,
+	}
+	s.layout.writeRule(s.ew, s.opts.Border.Top, false)
+	s.writeHeader()
+}
+
+func (s *StreamWriter) writeHeader() {
+	s.layout.writeRow(s.ew, s.headers)
+	s.layout.writeRule(s.ew, s.opts.Border.Header, s.opts.Border.MarkAlignment)
+	s.written = 0
+}
+
+func (s *StreamWriter) writeRow(row []string) {
+	if s.opts.HeaderEvery > 0 && s.written == s.opts.HeaderEvery {
+		s.layout.writeRule(s.ew, s.opts.Border.Header, false)
+		s.writeHeader()
+	}
+	for i, cell := range row {
+		row[i] = s.table.displayCell(cell, i)
+	}
+	s.layout.writeRow(s.ew, row)
+	s.written++
+}
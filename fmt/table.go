@@ -0,0 +1,167 @@
+package main
+
+import "slices"
+
+// Alignment is where a cell's text sits inside its column.
+type Alignment int
+
+const (
+	AlignAuto   Alignment = iota // right for numeric columns, left for everything else
+	AlignLeft                    // |Daniel    |
+	AlignRight                   // |    Daniel|
+	AlignCenter                  // |  Daniel  |
+)
+
+// Column holds optional settings for one column of a TableData.
+// Columns are matched to Headers by position, a missing entry means "use the defaults".
+type Column struct {
+	MinWidth int           // never render the column narrower than this, 0 means no minimum
+	MaxWidth int           // never render the column wider than this, 0 means no maximum
+	Align    Alignment     // AlignAuto when not set
+	Number   *NumberFormat // when set, cells are parsed as numbers and reformatted on render
+}
+
+// column returns the settings for column i, or the zero Column if none were given.
+func (t TableData) column(i int) Column {
+	if i < len(t.Columns) {
+		return t.Columns[i]
+	}
+	return Column{}
+}
+
+// columnCount is the number of columns the table needs to draw,
+// which is the header count unless a row is longer than the header.
+func (t TableData) columnCount() int {
+	n := len(t.Headers)
+	for _, row := range t.Rows {
+		n = max(n, len(row))
+	}
+	for _, row := range t.Footer {
+		n = max(n, len(row))
+	}
+	return n
+}
+
+// columnIndex returns the position of the column with the given header, or -1.
+func (t TableData) columnIndex(header string) int {
+	return slices.Index(t.Headers, header)
+}
+
+// isNumeric reports whether column i holds numbers: it has a NumberFormat,
+// or every non-empty cell in it parses as one.
+func (t TableData) isNumeric(i int) bool {
+	if t.column(i).Number != nil {
+		return true
+	}
+	found := false
+	for _, row := range t.Rows {
+		cell := cellAt(row, i)
+		if cell == "" {
+			continue
+		}
+		if _, ok := ParseNumber(cell); !ok {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// alignments resolves AlignAuto for every column.
+func (t TableData) alignments() []Alignment {
+	aligns := make([]Alignment, t.columnCount())
+	for i := range aligns {
+		aligns[i] = t.column(i).Align
+		if aligns[i] != AlignAuto {
+			continue
+		}
+		aligns[i] = AlignLeft
+		if t.isNumeric(i) {
+			aligns[i] = AlignRight
+		}
+	}
+	return aligns
+}
+
+// displayCell is the text shown for a cell: numeric columns go through their
+// NumberFormat, everything else (and cells that don't parse) is shown as is.
+func (t TableData) displayCell(cell string, col int) string {
+	nf := t.column(col).Number
+	if nf == nil {
+		return cell
+	}
+	if v, ok := ParseNumber(cell); ok {
+		return nf.Format(v)
+	}
+	return cell
+}
+
+// displayRows returns a copy of Rows with every cell as it should be shown.
+func (t TableData) displayRows() [][]string {
+	return t.display(t.Rows, t.plainRows)
+}
+
+// records returns the Rows without the subtotals GroupBy added, for the
+// formats that write one record per row.
+func (t TableData) records() [][]string {
+	if len(t.subtotalRows) == 0 {
+		return t.Rows
+	}
+	var rows [][]string
+	for r, row := range t.Rows {
+		if r >= len(t.subtotalRows) || !t.subtotalRows[r] {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// displayFooter is displayRows for the Footer.
+func (t TableData) displayFooter() [][]string {
+	return t.display(t.Footer, t.plainFooter)
+}
+
+func (t TableData) display(rows [][]string, plain []bool) [][]string {
+	out := make([][]string, len(rows))
+	for r, row := range rows {
+		if r < len(plain) && plain[r] {
+			out[r] = append([]string(nil), row...)
+			continue
+		}
+		out[r] = make([]string, len(row))
+		for i, cell := range row {
+			out[r][i] = t.displayCell(cell, i)
+		}
+	}
+	return out
+}
+
+// ColumnWidths returns the display width of every column: the widest header
+// or (formatted) cell line in that column, clamped to the column's MinWidth and MaxWidth.
+func (t TableData) ColumnWidths() []int {
+	widths := make([]int, t.columnCount())
+	for i, h := range t.Headers {
+		widths[i] = cellWidth(h)
+	}
+	for _, row := range append(t.displayRows(), t.displayFooter()...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], cellWidth(cell))
+		}
+	}
+
+	for i := range widths {
+		c := t.column(i)
+		if c.MinWidth > 0 {
+			widths[i] = max(widths[i], c.MinWidth)
+		}
+		if c.MaxWidth > 0 {
+			widths[i] = min(widths[i], c.MaxWidth)
+		}
+	}
+	return widths
+}
+
+// fitCell pads or cuts cell so it takes up exactly width cells.
+func fitCell(cell string, width int, align Alignment) string {
+	return alignCell(truncateWidth(cell, width), width, align)
+}
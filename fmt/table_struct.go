@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// structColumn is one exported struct field that becomes a table column.
+type structColumn struct {
+	index  []int // field index path, longer than 1 for fields of embedded structs
+	header string
+	format string // fmt format used for the cell, "%v" by default
+	column Column
+}
+
+// NewTableFromStructs builds a TableData from a slice of structs, or pointers
+// to structs, with one column per exported field and one row per item.
+//
+// A field's `table` tag changes how its column looks:
+//
+//	Balance float64 `table:"Account Balance,right,format=%.2f"`
+//	Secret  string  `table:"-"` // not shown
+//
+// The first part is the header (the field name when empty), after that come
+// options: left, right or center for the alignment, format=<verb> for the fmt
+// format of each cell (no commas), and min=<n> / max=<n> for the column width.
+//
+// Cells are made with fmt.Sprintf, so a field whose type implements
+// fmt.Formatter or fmt.Stringer is printed with it. Nil pointers are empty cells.
+func NewTableFromStructs[T any](items []T) (TableData, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return TableData{}, fmt.Errorf("NewTableFromStructs: %v is not a struct or a pointer to a struct", typ)
+	}
+
+	columns, err := structColumns(typ, nil)
+	if err != nil {
+		return TableData{}, err
+	}
+
+	table := TableData{
+		Headers: make([]string, len(columns)),
+		Columns: make([]Column, len(columns)),
+	}
+	for i, c := range columns {
+		table.Headers[i] = c.header
+		table.Columns[i] = c.column
+	}
+
+	for _, item := range items {
+		v := reflect.ValueOf(&item).Elem()
+		if v.Kind() == reflect.Pointer {
+			v = v.Elem()
+		}
+		row := make([]string, len(columns))
+		if v.IsValid() {
+			for i, c := range columns {
+				row[i] = formatField(v, c)
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// structColumns lists the columns for the exported fields of typ, in order.
+// Fields of embedded structs are pulled up, the way encoding/json does it.
+func structColumns(typ reflect.Type, parent []int) ([]structColumn, error) {
+	var columns []structColumn
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		index := append(append([]int(nil), parent...), i)
+		tag, hasTag := field.Tag.Lookup("table")
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && field.IsExported() && field.Type.Kind() == reflect.Struct && !hasTag {
+			embedded, err := structColumns(field.Type, index)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, embedded...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		c, err := parseTableTag(field.Name, tag)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		c.index = index
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
+
+// parseTableTag reads a tag like "Account Balance,right,format=%.2f".
+func parseTableTag(fieldName, tag string) (structColumn, error) {
+	parts := strings.Split(tag, ",")
+	c := structColumn{header: strings.TrimSpace(parts[0]), format: "%v"}
+	if c.header == "" {
+		c.header = fieldName
+	}
+
+	for _, opt := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch key {
+		case "":
+		case "left":
+			c.column.Align = AlignLeft
+		case "right":
+			c.column.Align = AlignRight
+		case "center":
+			c.column.Align = AlignCenter
+		case "format":
+			if !strings.Contains(value, "%") {
+				return c, fmt.Errorf("format %q has no %% verb", value)
+			}
+			c.format = value
+		case "min", "max":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return c, fmt.Errorf("%s=%q is not a width", key, value)
+			}
+			if key == "min" {
+				c.column.MinWidth = n
+			} else {
+				c.column.MaxWidth = n
+			}
+		default:
+			return c, fmt.Errorf("unknown table tag option %q", key)
+		}
+	}
+	return c, nil
+}
+
+// formatField renders the field of v described by c.
+// A pointer is followed to its value, fmt would print the address otherwise,
+// unless the pointer type itself has a String or Format method.
+func formatField(v reflect.Value, c structColumn) string {
+	v = v.FieldByIndex(c.index)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return ""
+		}
+	}
+	if v.Kind() == reflect.Pointer && !hasFormatMethod(v.Type()) {
+		v = v.Elem()
+	}
+	return fmt.Sprintf(c.format, v.Interface())
+}
+
+var (
+	stringerType  = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	formatterType = reflect.TypeOf((*fmt.Formatter)(nil)).Elem()
+)
+
+func hasFormatMethod(t reflect.Type) bool {
+	return t.Implements(stringerType) || t.Implements(formatterType)
+}
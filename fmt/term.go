@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+	"strconv"
+)
+
+// TerminalWidth returns how many columns the terminal behind f has.
+// When f isn't a terminal it falls back to the COLUMNS environment variable,
+// and returns 0 ("no limit") when that isn't set either.
+func TerminalWidth(f *os.File) int {
+	if cols, ok := terminalColumns(f.Fd()); ok {
+		return cols
+	}
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	return 0
+}
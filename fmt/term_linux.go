@@ -0,0 +1,33 @@
+//go:build linux
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// winsize is the struct the TIOCGWINSZ ioctl fills in, see ioctl_tty(2).
+type winsize struct {
+	Row, Col       uint16
+	Xpixel, Ypixel uint16
+}
+
+// terminalColumns asks the terminal behind fd how many columns it has.
+// It fails when fd isn't a terminal, e.g. when the output is piped to a file.
+func terminalColumns(fd uintptr) (int, bool) {
+	var ws winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 || ws.Col == 0 {
+		return 0, false
+	}
+	return int(ws.Col), true
+}
+
+// isTerminal reports whether fd is a terminal. Only terminals answer the
+// TCGETS ioctl, files and pipes fail it with ENOTTY.
+func isTerminal(fd uintptr) bool {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCGETS, uintptr(unsafe.Pointer(&termios)))
+	return errno == 0
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package main
+
+// terminalColumns only knows how to ask a Linux terminal, elsewhere
+// TerminalWidth falls back to $COLUMNS.
+func terminalColumns(fd uintptr) (int, bool) {
+	return 0, false
+}
+
+// isTerminal can't tell outside Linux, so ColorAuto never colors there.
+func isTerminal(fd uintptr) bool {
+	return false
+}
@@ -0,0 +1,490 @@
+// explain takes a Printf format string apart and says what every piece of it
+// does, the width and precision notes in fmt/main.go as a tool:
+//
+//	go run ./fmt/tools/explain '|%-10.3f|%+08d|%#x|' 3.14159 42 255
+//
+// Sample arguments after the format are printed with it. They are numbers,
+// booleans or strings, whatever they look like; a prefix such as string:42,
+// float:1, uint:7 or rune:A picks the type.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// directive is one %... in a format string.
+type directive struct {
+	text      string // the directive as written, e.g. "%-10.3f"
+	column    int    // 1-based column of the '%'
+	flags     string
+	width     string // digits, "*" or "" when there is none
+	precision string // digits, "*" or "" ("." alone is precision 0)
+	hasPrec   bool
+	verb      rune
+	args      []argUse // every argument the directive takes, * ones first
+}
+
+// argUse is an argument a directive consumes, for the width, the precision or the verb.
+type argUse struct {
+	index    int    // 0-based
+	role     string // "width", "precision" or "value"
+	explicit bool   // chosen with [n]
+}
+
+// diagnostic is a problem at a column of the format.
+type diagnostic struct {
+	column int
+	msg    string
+	fatal  bool
+}
+
+// parser walks a format string the way fmt does, see doPrintf in fmt/print.go.
+type parser struct {
+	format    []rune
+	i         int
+	argNum    int  // the next argument to use
+	used      int  // one past the highest argument used
+	reordered bool // an [n] index was used
+	diags     []diagnostic
+}
+
+// parse returns the directives of format and everything wrong with it, and
+// how many arguments the format uses. Literal text between directives is skipped.
+func parse(format string) ([]directive, []diagnostic, argCount) {
+	p := &parser{format: []rune(format)}
+	var directives []directive
+	for p.i < len(p.format) {
+		if p.format[p.i] != '%' {
+			p.i++
+			continue
+		}
+		if p.i+1 < len(p.format) && p.format[p.i+1] == '%' {
+			p.i += 2
+			continue
+		}
+		if d, ok := p.directive(); ok {
+			directives = append(directives, d)
+		}
+	}
+	return directives, p.diags, argCount{used: p.used, reordered: p.reordered}
+}
+
+// argCount is how many arguments a format uses: one past the highest it
+// takes, which with [n] indexes needn't be the last one.
+type argCount struct {
+	used      int
+	reordered bool
+}
+
+// check warns when given arguments don't match the count. Like fmt, which
+// only prints %!(EXTRA ...) for formats without [n], extra arguments are
+// fine once indexes reorder them.
+func (c argCount) check(given int) []diagnostic {
+	switch {
+	case c.used > given:
+		return []diagnostic{{msg: fmt.Sprintf("the format uses %d arguments but %d were given", c.used, given)}}
+	case c.used < given && !c.reordered:
+		return []diagnostic{{msg: fmt.Sprintf("%d arguments were given but the format only uses %d", given, c.used)}}
+	}
+	return nil
+}
+
+func (p *parser) errorf(column int, format string, args ...any) {
+	p.diags = append(p.diags, diagnostic{column: column, msg: fmt.Sprintf(format, args...), fatal: true})
+}
+
+func (p *parser) warnf(column int, format string, args ...any) {
+	p.diags = append(p.diags, diagnostic{column: column, msg: fmt.Sprintf(format, args...)})
+}
+
+func (p *parser) peek() (rune, bool) {
+	if p.i < len(p.format) {
+		return p.format[p.i], true
+	}
+	return 0, false
+}
+
+// directive reads one directive starting at the '%' under p.i.
+func (p *parser) directive() (directive, bool) {
+	start := p.i
+	d := directive{column: start + 1}
+	p.i++
+
+	for r, ok := p.peek(); ok && strings.ContainsRune("+-# 0", r); r, ok = p.peek() {
+		if strings.ContainsRune(d.flags, r) {
+			p.warnf(p.i+1, "flag %q is given twice", r)
+		} else {
+			d.flags += string(r)
+		}
+		p.i++
+	}
+
+	// [n] before the width or the verb picks the argument
+	explicit, ok := p.argIndex()
+	if !ok {
+		return d, false
+	}
+	if r, _ := p.peek(); r == '*' {
+		p.i++
+		d.width = "*"
+		p.take(&d, "width", explicit)
+		explicit = false
+	} else {
+		d.width = p.digits()
+	}
+
+	if r, _ := p.peek(); r == '.' {
+		p.i++
+		d.hasPrec = true
+		if explicit, ok = p.argIndex(); !ok {
+			return d, false
+		}
+		if r, _ := p.peek(); r == '*' {
+			p.i++
+			d.precision = "*"
+			p.take(&d, "precision", explicit)
+			explicit = false
+		} else {
+			d.precision = p.digits()
+		}
+	}
+
+	if !explicit {
+		if explicit, ok = p.argIndex(); !ok {
+			return d, false
+		}
+	}
+
+	verb, ok := p.peek()
+	if !ok {
+		p.errorf(len(p.format)+1, "missing verb at the end of %q", string(p.format[start:]))
+		return d, false
+	}
+	p.i++
+	d.verb = verb
+	d.text = string(p.format[start:p.i])
+	// fmt uses up an argument even for a verb it doesn't know
+	p.take(&d, "value", explicit)
+	if _, known := verbs[verb]; !known {
+		p.errorf(p.i, "unknown verb %q in %s", verb, d.text)
+		return d, false
+	}
+
+	p.check(d)
+	return d, true
+}
+
+// take records that d uses the next argument for role.
+func (p *parser) take(d *directive, role string, explicit bool) {
+	d.args = append(d.args, argUse{index: p.argNum, role: role, explicit: explicit})
+	p.used = max(p.used, p.argNum+1)
+	p.argNum++
+}
+
+// argIndex reads an optional "[n]" and moves argNum to it.
+func (p *parser) argIndex() (explicit, ok bool) {
+	if r, _ := p.peek(); r != '[' {
+		return false, true
+	}
+	open := p.i
+	end := open + 1
+	for end < len(p.format) && p.format[end] != ']' {
+		end++
+	}
+	if end >= len(p.format) {
+		p.errorf(open+1, "argument index is missing its ']'")
+		p.i = len(p.format)
+		return false, false
+	}
+	text := string(p.format[open+1 : end])
+	n, err := strconv.Atoi(text)
+	if err != nil || n < 1 {
+		p.errorf(open+2, "bad argument index [%s], it must be a number from 1", text)
+		p.i = end + 1
+		return false, false
+	}
+	p.i = end + 1
+	p.argNum = n - 1
+	p.reordered = true
+	return true, true
+}
+
+func (p *parser) digits() string {
+	start := p.i
+	for r, ok := p.peek(); ok && r >= '0' && r <= '9'; r, ok = p.peek() {
+		p.i++
+	}
+	return string(p.format[start:p.i])
+}
+
+// check warns about flags and precisions that do nothing for the verb.
+func (p *parser) check(d directive) {
+	if strings.Contains(d.flags, "-") && strings.Contains(d.flags, "0") {
+		p.warnf(d.column, "%s: the 0 flag is ignored with -, the padding goes on the right", d.text)
+	}
+	if strings.Contains(d.flags, "#") && !strings.ContainsRune("vxXoObqeEfFgGU", d.verb) {
+		p.warnf(d.column, "%s: the # flag does nothing for %%%c", d.text, d.verb)
+	}
+	if d.hasPrec && strings.ContainsRune("ctTpU", d.verb) {
+		p.warnf(d.column, "%s: precision does nothing for %%%c", d.text, d.verb)
+	}
+	if d.verb == 'w' {
+		p.warnf(d.column, "%s: %%w only works in fmt.Errorf, Printf prints %%!w", d.text)
+	}
+}
+
+// verbs explains every verb fmt knows.
+var verbs = map[rune]string{
+	'v': "the value in its default format",
+	'T': "the Go type of the value, e.g. float64",
+	't': "the word true or false",
+	'b': "the number in binary (base 2)",
+	'c': "the character with this Unicode code point",
+	'd': "the number in decimal (base 10)",
+	'o': "the number in octal (base 8)",
+	'O': "the number in octal with a 0o prefix",
+	'q': "a Go string or character literal, quoted and escaped",
+	'x': "hexadecimal with a-f, for numbers, strings and byte slices",
+	'X': "hexadecimal with A-F, for numbers, strings and byte slices",
+	'U': "the Unicode format, U+1234",
+	'e': "scientific notation, -1.234456e+78",
+	'E': "scientific notation, -1.234456E+78",
+	'f': "a decimal point and no exponent, 123.456000",
+	'F': "the same as %f",
+	'g': "%e for large exponents, %f otherwise, only the digits needed",
+	'G': "%E for large exponents, %F otherwise, only the digits needed",
+	's': "the uninterpreted bytes of a string or slice, or the String/Error method",
+	'p': "the address of a pointer, in hex with a 0x prefix",
+	'w': "the error, wrapped so errors.Is and errors.Unwrap can find it (fmt.Errorf only)",
+}
+
+// explainFlag says what a flag does for verb.
+func explainFlag(flag, verb rune) string {
+	switch flag {
+	case '-':
+		return "left-justify: pad with spaces on the right instead of the left"
+	case '+':
+		switch verb {
+		case 'q':
+			return "only ASCII in the output, everything else is escaped"
+		case 'v':
+			return "add the field names of structs"
+		}
+		return "always print a sign, + for positive numbers too"
+	case '#':
+		switch verb {
+		case 'x', 'X':
+			return "add the 0x (or 0X) prefix"
+		case 'o':
+			return "add a leading 0"
+		case 'b':
+			return "add the 0b prefix"
+		case 'q':
+			return "use a `raw string` when it can"
+		case 'v':
+			return "Go syntax, the value as it would be written in code"
+		case 'U':
+			return "add the character too, U+0041 'A'"
+		}
+		return "always print a decimal point, and keep trailing zeros for %g"
+	case ' ':
+		if verb == 'x' || verb == 'X' {
+			return "put spaces between the bytes"
+		}
+		return "leave a space where the + of a positive number would go"
+	case '0':
+		return "pad with leading zeros instead of spaces, after the sign"
+	}
+	return ""
+}
+
+// explainPrecision says what the precision means for verb.
+func explainPrecision(p string, verb rune) string {
+	if p == "" {
+		p = "0"
+	}
+	switch verb {
+	case 'e', 'E', 'f', 'F':
+		return p + " digits after the decimal point"
+	case 'g', 'G':
+		return "at most " + p + " significant digits"
+	case 's', 'q', 'x', 'X', 'v':
+		return "for strings, at most " + p + " characters (runes); for numbers, the digits as with %f or %d"
+	case 'd', 'b', 'o', 'O':
+		return "at least " + p + " digits, with leading zeros"
+	}
+	return p
+}
+
+func ordinal(n int) string {
+	return "argument " + strconv.Itoa(n+1)
+}
+
+// explain prints what d does, and what it makes of args when there are any.
+func explain(d directive, args []any) {
+	fmt.Printf("%s (column %d)\n", d.text, d.column)
+	for _, f := range d.flags {
+		fmt.Printf("  %-8q flag: %s\n", f, explainFlag(f, d.verb))
+	}
+
+	for _, use := range d.args {
+		if use.explicit {
+			fmt.Printf("  %-8s index: the %s comes from %s, the next directive goes on from there\n",
+				"["+strconv.Itoa(use.index+1)+"]", use.role, ordinal(use.index))
+		}
+	}
+
+	pad := "spaces on the left"
+	switch {
+	case strings.Contains(d.flags, "-"):
+		pad = "spaces on the right"
+	case strings.Contains(d.flags, "0"):
+		pad = "zeros on the left"
+	}
+	switch d.width {
+	case "":
+	case "*":
+		fmt.Printf("  %-8s width: at least as many characters as %s says (an int), padded with %s\n",
+			"*", ordinal(d.args[0].index), pad)
+	default:
+		fmt.Printf("  %-8s width: at least %s characters, padded with %s\n", d.width, d.width, pad)
+	}
+
+	if d.hasPrec {
+		if d.precision == "*" {
+			fmt.Printf("  %-8s precision: taken from %s (an int)\n", ".*", ordinal(d.args[len(d.args)-2].index))
+		} else {
+			fmt.Printf("  %-8s precision: %s\n", "."+d.precision, explainPrecision(d.precision, d.verb))
+		}
+	}
+
+	value := d.args[len(d.args)-1]
+	fmt.Printf("  %-8q verb: %s, from %s\n", d.verb, verbs[d.verb], ordinal(value.index))
+
+	if len(args) == 0 {
+		return
+	}
+	// Print the directive on its own with the arguments it takes.
+	used := make([]any, 0, len(d.args))
+	for _, use := range d.args {
+		if use.index >= len(args) {
+			fmt.Printf("  => %s is missing\n", ordinal(use.index))
+			return
+		}
+		used = append(used, args[use.index])
+	}
+	fmt.Printf("  => %q from %s\n", fmt.Sprintf(withoutIndexes(d), used...), describe(used[len(used)-1]))
+}
+
+// withoutIndexes rebuilds the directive without [n], so it can be printed
+// with just the arguments it uses.
+func withoutIndexes(d directive) string {
+	s := "%" + d.flags + d.width
+	if d.hasPrec {
+		s += "." + d.precision
+	}
+	return s + string(d.verb)
+}
+
+func describe(v any) string {
+	return fmt.Sprintf("%#v (%T)", v, v)
+}
+
+// parseArg turns a command-line argument into a value, see the top of the file.
+func parseArg(s string) (any, error) {
+	if kind, value, ok := strings.Cut(s, ":"); ok {
+		switch kind {
+		case "string":
+			return value, nil
+		case "int":
+			return strconv.Atoi(value)
+		case "uint":
+			n, err := strconv.ParseUint(value, 0, 64)
+			return uint(n), err
+		case "float":
+			return strconv.ParseFloat(value, 64)
+		case "bool":
+			return strconv.ParseBool(value)
+		case "rune":
+			r, size := utf8.DecodeRuneInString(value)
+			if size == 0 || size != len(value) {
+				return nil, fmt.Errorf("rune:%s is not a single character", value)
+			}
+			return r, nil
+		}
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	if b, err := strconv.ParseBool(s); err == nil {
+		return b, nil
+	}
+	return s, nil
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: explain 'format' [arguments...]\n\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "e.g. explain '|%%-10.3f|%%+08d|%%#x|' 3.14159 42 255\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	format := flag.Arg(0)
+	var args []any
+	for _, s := range flag.Args()[1:] {
+		v, err := parseArg(s)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bad argument %q: %v\n", s, err)
+			os.Exit(2)
+		}
+		args = append(args, v)
+	}
+
+	directives, diags, count := parse(format)
+	for _, d := range directives {
+		explain(d, args)
+		fmt.Println()
+	}
+	if len(args) > 0 {
+		diags = append(diags, count.check(len(args))...)
+		fmt.Printf("Result: %q\n", fmt.Sprintf(format, args...))
+	}
+
+	failed := false
+	for _, d := range diags {
+		level := "warning"
+		if d.fatal {
+			level, failed = "error", true
+		}
+		if d.column == 0 {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", level, d.msg)
+			continue
+		}
+		// point at the column under the format
+		fmt.Fprintf(os.Stderr, "%s: column %d: %s\n  %s\n  %s^\n", level, d.column, d.msg, format,
+			strings.Repeat(" ", displayColumn(format, d.column)))
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// displayColumn is how many characters come before the rune at column,
+// which is where the caret goes.
+func displayColumn(s string, column int) int {
+	return min(column-1, utf8.RuneCountInString(s))
+}
@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestArgCount(t *testing.T) {
+	tests := []struct {
+		format string
+		given  int
+		want   argCount
+		warns  bool
+	}{
+		{"%d %s", 2, argCount{used: 2}, false},
+		{"%d", 2, argCount{used: 1}, true},
+		{"%d %d", 1, argCount{used: 2}, true},
+		{"%*d", 2, argCount{used: 2}, false},
+		{"%[2]d %[1]d", 2, argCount{used: 2, reordered: true}, false},
+		{"%[1]d", 3, argCount{used: 1, reordered: true}, false},
+		{"%[3]d", 2, argCount{used: 3, reordered: true}, true},
+		{"%z %d", 2, argCount{used: 2}, false},
+	}
+	for _, tt := range tests {
+		_, _, got := parse(tt.format)
+		if got != tt.want {
+			t.Errorf("parse(%q) count = %+v, want %+v", tt.format, got, tt.want)
+		}
+		if warns := len(got.check(tt.given)) > 0; warns != tt.warns {
+			t.Errorf("%q with %d arguments: warning = %v, want %v", tt.format, tt.given, warns, tt.warns)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Struct Validation
+
+// Instead of checking every field by hand, the rules go into a `validate`
+// tag and a Validator checks them all, reporting every failing field:
+//
+//	type Person struct {
+//		Name string `validate:"required"`
+//		Age  int    `validate:"min=0,max=150"`
+//		City string `validate:"oneof=Lagos Abuja Ibadan"`
+//	}
+//
+// Rules are separated by commas and take their parameter after "=".
+// Built in are required, min, max, len and oneof, more can be added with
+// Register. Everything after "dive" is checked on each element of a slice,
+// array or map instead of the field itself: `validate:"max=3,dive,required"`.
+//
+// Fields are named by their json tag when they have one, so errors point at
+// "address.city" or "tags[2]", the same names a client sent.
+
+// RuleFunc checks one value. param is the text after "=" in the tag, "" when
+// there is none. It returns nil when the value passes, or an error saying
+// what is wrong; a ValidationError keeps its Code, anything else gets the
+// rule's name. Errors wrapping ErrBadRule mean the tag itself is wrong.
+type RuleFunc func(v reflect.Value, param string) error
+
+// ErrBadRule is returned by Validate for tags that can't be checked, like
+// "min=abc", an unknown rule or "len" on a number.
+var ErrBadRule = errors.New("bad validation rule")
+
+// Validator checks structs against their `validate` tags.
+type Validator struct {
+	rules map[string]RuleFunc
+}
+
+// NewValidator returns a Validator with the built-in rules.
+func NewValidator() *Validator {
+	v := &Validator{rules: map[string]RuleFunc{}}
+	v.Register(CodeRequired, ruleRequired)
+	v.Register(CodeMin, ruleMin)
+	v.Register(CodeMax, ruleMax)
+	v.Register(CodeLen, ruleLen)
+	v.Register(CodeOneOf, ruleOneOf)
+	return v
+}
+
+// Register adds a rule under name, or replaces the one already there.
+func (v *Validator) Register(name string, rule RuleFunc) {
+	v.rules[name] = rule
+}
+
+// Validate checks x, a struct or a pointer to one, along with every struct
+// it holds in its fields, slices and maps. It returns nil, ValidationErrors
+// listing every failing field, or an error wrapping ErrBadRule.
+func (v *Validator) Validate(x any) error {
+	run := &validation{visiting: map[visitKey]bool{}}
+	if err := v.walk(reflect.ValueOf(x), "", run); err != nil {
+		return err
+	}
+	return run.errs.Err()
+}
+
+// validation is one run of Validate.
+type validation struct {
+	errs     ValidationErrors
+	visiting map[visitKey]bool // pointers, maps and slices being walked, so a cycle is walked once
+}
+
+type visitKey struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+// enter marks val as being walked, and reports false when it already was.
+func (run *validation) enter(val reflect.Value) bool {
+	key := visitKey{typ: val.Type(), ptr: val.Pointer()}
+	if val.Kind() == reflect.Slice {
+		key.len = val.Len()
+	}
+	if run.visiting[key] {
+		return false
+	}
+	run.visiting[key] = true
+	return true
+}
+
+func (run *validation) leave(val reflect.Value) {
+	key := visitKey{typ: val.Type(), ptr: val.Pointer()}
+	if val.Kind() == reflect.Slice {
+		key.len = val.Len()
+	}
+	delete(run.visiting, key)
+}
+
+// walk checks the tagged fields of every struct in val. A value that is
+// already being walked further up, like u.Self = &u, isn't walked again.
+func (v *Validator) walk(val reflect.Value, path string, run *validation) error {
+	for val.Kind() == reflect.Pointer || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		if val.Kind() == reflect.Pointer {
+			if !run.enter(val) {
+				return nil
+			}
+			defer run.leave(val)
+		}
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.Struct:
+		typ := val.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			tag := field.Tag.Get("validate")
+			if !field.IsExported() || tag == "-" {
+				continue
+			}
+			fieldPath := joinPath(path, fieldName(field))
+			if field.Anonymous && tag == "" {
+				// embedded fields belong to the outer struct, like in encoding/json
+				fieldPath = path
+			}
+			if err := v.check(val.Field(i), fieldPath, tag, run); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Map:
+		if val.IsNil() || !run.enter(val) {
+			return nil
+		}
+		defer run.leave(val)
+		return v.each(val, path, "", run)
+	case reflect.Array:
+		return v.each(val, path, "", run)
+	}
+	return nil
+}
+
+// check runs the rules in tag on val, then looks inside it.
+func (v *Validator) check(val reflect.Value, path, tag string, run *validation) error {
+	var rules []string
+	if tag != "" {
+		rules = strings.Split(tag, ",")
+	}
+	dive := slices.Index(rules, "dive")
+	elemTag := ""
+	if dive >= 0 {
+		elemTag = strings.Join(rules[dive+1:], ",")
+		rules = rules[:dive]
+	}
+
+	if err := v.apply(val, path, rules, run); err != nil {
+		return err
+	}
+	if dive >= 0 {
+		return v.each(indirect(val), path, elemTag, run)
+	}
+	return v.walk(val, path, run)
+}
+
+// each checks every element of a slice, array or map against tag.
+// Map keys are visited in sorted order so the errors come out the same every time.
+func (v *Validator) each(val reflect.Value, path, tag string, run *validation) error {
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			if err := v.check(val.Index(i), fmt.Sprintf("%s[%d]", path, i), tag, run); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		keys := val.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+		})
+		for _, key := range keys {
+			if err := v.check(val.MapIndex(key), fmt.Sprintf("%s[%v]", path, key), tag, run); err != nil {
+				return err
+			}
+		}
+	default:
+		if tag != "" {
+			return fmt.Errorf("%s: %w: dive on a %s", path, ErrBadRule, val.Kind())
+		}
+	}
+	return nil
+}
+
+// apply runs rules on one value and adds a ValidationError for every rule
+// that fails. A nil pointer only fails required, the other rules are skipped.
+func (v *Validator) apply(val reflect.Value, path string, rules []string, run *validation) error {
+	target := indirect(val)
+	for _, r := range rules {
+		name, param, _ := strings.Cut(strings.TrimSpace(r), "=")
+		if name == "" {
+			continue
+		}
+		rule, ok := v.rules[name]
+		if !ok {
+			return fmt.Errorf("%s: %w: unknown rule %q", path, ErrBadRule, name)
+		}
+
+		var err error
+		switch {
+		case target.IsValid():
+			err = rule(target, param)
+		case name == CodeRequired:
+			err = ErrRequired
+		default:
+			continue
+		}
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, ErrBadRule) {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		failure := ValidationError{Field: path, ErrorMessage: err.Error(), Code: name}
+		var ve ValidationError
+		if errors.As(err, &ve) {
+			failure.ErrorMessage = ve.ErrorMessage
+			if ve.Code != "" {
+				failure.Code = ve.Code
+			}
+		}
+		if target.IsValid() && target.CanInterface() {
+			failure.Value = target.Interface()
+		}
+		run.errs.Add(failure)
+		if name == CodeRequired {
+			// the other rules would only repeat that the value is missing
+			break
+		}
+	}
+	return nil
+}
+
+// indirect follows pointers and interfaces to the value they hold.
+// The result is the zero Value when one of them is nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// fieldName is the json name of a field, or its Go name when it has none.
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// The built-in rules.
+
+func ruleRequired(v reflect.Value, _ string) error {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		if v.Len() == 0 {
+			return ErrRequired
+		}
+	default:
+		if v.IsZero() {
+			return ErrRequired
+		}
+	}
+	return nil
+}
+
+// size is what min, max and len compare: the value of a number, the
+// characters in a string and the items in a slice, array or map.
+func size(v reflect.Value) (n float64, unit string, ok bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "", true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), "characters", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), "items", true
+	}
+	return 0, "", false
+}
+
+// bound reads the parameter of min, max or len and the size of v.
+func bound(v reflect.Value, rule, param string) (n, limit float64, unit string, err error) {
+	limit, err = strconv.ParseFloat(param, 64)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("%w: %s=%q is not a number", ErrBadRule, rule, param)
+	}
+	n, unit, ok := size(v)
+	if !ok {
+		return 0, 0, "", fmt.Errorf("%w: %s on a %s", ErrBadRule, rule, v.Kind())
+	}
+	return n, limit, unit, nil
+}
+
+func ruleMin(v reflect.Value, param string) error {
+	n, limit, unit, err := bound(v, CodeMin, param)
+	switch {
+	case err != nil:
+		return err
+	case n >= limit:
+		return nil
+	case unit == "":
+		return fmt.Errorf("must be at least %s", param)
+	case unit == "items":
+		return fmt.Errorf("must have at least %s items", param)
+	}
+	return fmt.Errorf("must be at least %s characters long", param)
+}
+
+func ruleMax(v reflect.Value, param string) error {
+	n, limit, unit, err := bound(v, CodeMax, param)
+	switch {
+	case err != nil:
+		return err
+	case n <= limit:
+		return nil
+	case unit == "":
+		return fmt.Errorf("must be at most %s", param)
+	case unit == "items":
+		return fmt.Errorf("must have at most %s items", param)
+	}
+	return fmt.Errorf("must be at most %s characters long", param)
+}
+
+func ruleLen(v reflect.Value, param string) error {
+	n, limit, unit, err := bound(v, CodeLen, param)
+	switch {
+	case err != nil:
+		return err
+	case unit == "":
+		return fmt.Errorf("%w: len on a %s, use min and max for numbers", ErrBadRule, v.Kind())
+	case n == limit:
+		return nil
+	case unit == "items":
+		return fmt.Errorf("must have exactly %s items", param)
+	}
+	return fmt.Errorf("must be exactly %s characters long", param)
+}
+
+// ruleOneOf takes the allowed values separated by spaces: oneof=Lagos Abuja Ibadan.
+func ruleOneOf(v reflect.Value, param string) error {
+	allowed := strings.Fields(param)
+	if len(allowed) == 0 {
+		return fmt.Errorf("%w: oneof without any values", ErrBadRule)
+	}
+	if slices.Contains(allowed, fmt.Sprint(v.Interface())) {
+		return nil
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+}
@@ -0,0 +1,120 @@
+package main
+
+import "strings"
+
+// Codes for ValidationError.Code, so callers can tell failures apart
+// without parsing the message.
+const (
+	CodeRequired = "required" // the field is empty
+	CodeMin      = "min"      // the number, length or count is too small
+	CodeMax      = "max"      // ... or too big
+	CodeLen      = "len"      // the length or count isn't exactly right
+	CodeOneOf    = "oneof"    // the value isn't one of the allowed ones
+	CodeInvalid  = "invalid"  // anything else
+)
+
+// Sentinels for errors.Is: errors.Is(err, ErrRequired) is true when err is,
+// or contains, a ValidationError with the code "required", for any field.
+var (
+	ErrRequired = ValidationError{Code: CodeRequired, ErrorMessage: "is required"}
+	ErrMin      = ValidationError{Code: CodeMin, ErrorMessage: "is too small"}
+	ErrMax      = ValidationError{Code: CodeMax, ErrorMessage: "is too big"}
+	ErrLen      = ValidationError{Code: CodeLen, ErrorMessage: "has the wrong length"}
+	ErrOneOf    = ValidationError{Code: CodeOneOf, ErrorMessage: "is not an allowed value"}
+	ErrInvalid  = ValidationError{Code: CodeInvalid, ErrorMessage: "is invalid"}
+)
+
+// Is matches a target ValidationError by Code, and by Field when the
+// target has one, so the message and value don't have to be the same.
+func (v ValidationError) Is(target error) bool {
+	var t ValidationError
+	switch target := target.(type) {
+	case ValidationError:
+		t = target
+	case *ValidationError:
+		if target == nil {
+			return false
+		}
+		t = *target
+	default:
+		return false
+	}
+	return t.Code != "" && t.Code == v.Code && (t.Field == "" || t.Field == v.Field)
+}
+
+// ValidationErrors is every field that failed validation, in the order
+// they were found.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap lets errors.Is and errors.As look at every ValidationError in the list.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Add appends err to the list. Every ValidationError in it is added, also
+// from inside wrapped and joined errors, anything else that isn't nil
+// becomes a ValidationError with CodeInvalid.
+func (e *ValidationErrors) Add(err error) {
+	if found := validationErrorsIn(err); len(found) > 0 {
+		*e = append(*e, found...)
+	} else if err != nil {
+		*e = append(*e, ValidationError{ErrorMessage: err.Error(), Code: CodeInvalid})
+	}
+}
+
+// validationErrorsIn finds every ValidationError in the tree of err, through
+// Unwrap() error and Unwrap() []error, in order. errors.As would stop at the first.
+func validationErrorsIn(err error) ValidationErrors {
+	switch e := err.(type) {
+	case ValidationError:
+		return ValidationErrors{e}
+	case *ValidationError:
+		if e != nil {
+			return ValidationErrors{*e}
+		}
+	case ValidationErrors:
+		return append(ValidationErrors(nil), e...)
+	case interface{ Unwrap() []error }:
+		var found ValidationErrors
+		for _, inner := range e.Unwrap() {
+			found = append(found, validationErrorsIn(inner)...)
+		}
+		return found
+	case interface{ Unwrap() error }:
+		return validationErrorsIn(e.Unwrap())
+	}
+	return nil
+}
+
+// Err returns the list as an error, or nil when it is empty. Return this
+// rather than the list itself: an empty ValidationErrors in an error
+// variable is not nil.
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Field returns the errors for one field path.
+func (e ValidationErrors) Field(path string) ValidationErrors {
+	var out ValidationErrors
+	for _, err := range e {
+		if err.Field == path {
+			out = append(out, err)
+		}
+	}
+	return out
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Display Width
+
+// fmt's width specifier (%-30s) pads by counting RUNES, not by how many
+// cells the text takes up on a terminal. That is fine for ASCII, but:
+//   - CJK characters and most emoji take up TWO cells ("世" is 1 rune, 2 cells)
+//   - combining marks take up ZERO cells ("é" can be "e" + U+0301)
+//   - a zero-width joiner glues emoji together ("👩‍💻" is 3 runes, 2 cells)
+// so we measure display width ourselves and pad with spaces by hand.
+// ANSI escape codes ("\x1b[31m" for red) are invisible and take up no cells.
+
+const (
+	zeroWidthJoiner = '\u200d'
+	variationSelect = '\ufe0f'
+)
+
+// wideRanges lists the East Asian Wide/Fullwidth blocks and the emoji blocks
+// that terminals draw two cells wide. It is not the full Unicode table, just
+// the ranges real-world table data tends to hit.
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115f},   // Hangul Jamo
+	{0x231a, 0x231b},   // watch, hourglass
+	{0x23e9, 0x23ec},   // media controls
+	{0x23f0, 0x23f0},   // alarm clock
+	{0x23f3, 0x23f3},   // hourglass with sand
+	{0x25fd, 0x25fe},   // medium small squares
+	{0x2614, 0x2615},   // umbrella, hot beverage
+	{0x2648, 0x2653},   // zodiac
+	{0x267f, 0x267f},   // wheelchair
+	{0x2693, 0x2693},   // anchor
+	{0x26a1, 0x26a1},   // high voltage
+	{0x26aa, 0x26ab},   // circles
+	{0x26bd, 0x26be},   // soccer, baseball
+	{0x26c4, 0x26c5},   // snowman, sun behind cloud
+	{0x26ce, 0x26ce},   // ophiuchus
+	{0x26d4, 0x26d4},   // no entry
+	{0x26ea, 0x26ea},   // church
+	{0x26f2, 0x26f3},   // fountain, golf
+	{0x26f5, 0x26f5},   // sailboat
+	{0x26fa, 0x26fa},   // tent
+	{0x26fd, 0x26fd},   // fuel pump
+	{0x2705, 0x2705},   // check mark button
+	{0x270a, 0x270b},   // raised fists
+	{0x2728, 0x2728},   // sparkles
+	{0x274c, 0x274c},   // cross mark
+	{0x274e, 0x274e},   // cross mark button
+	{0x2753, 0x2755},   // question marks
+	{0x2757, 0x2757},   // exclamation mark
+	{0x2795, 0x2797},   // math symbols
+	{0x27b0, 0x27b0},   // curly loop
+	{0x27bf, 0x27bf},   // double curly loop
+	{0x2b1b, 0x2b1c},   // large squares
+	{0x2b50, 0x2b50},   // star
+	{0x2b55, 0x2b55},   // circle
+	{0x2e80, 0x303e},   // CJK radicals, punctuation
+	{0x3041, 0x33ff},   // Hiragana, Katakana, CJK compatibility
+	{0x3400, 0x4dbf},   // CJK extension A
+	{0x4e00, 0x9fff},   // CJK unified ideographs
+	{0xa000, 0xa4cf},   // Yi
+	{0xa960, 0xa97f},   // Hangul Jamo extended A
+	{0xac00, 0xd7a3},   // Hangul syllables
+	{0xf900, 0xfaff},   // CJK compatibility ideographs
+	{0xfe10, 0xfe19},   // vertical forms
+	{0xfe30, 0xfe6f},   // CJK compatibility forms
+	{0xff00, 0xff60},   // fullwidth forms
+	{0xffe0, 0xffe6},   // fullwidth signs
+	{0x16fe0, 0x16fe4}, // ideographic symbols
+	{0x17000, 0x18aff}, // Tangut
+	{0x1b000, 0x1b2ff}, // Kana supplement
+	{0x1f004, 0x1f004}, // mahjong
+	{0x1f0cf, 0x1f0cf}, // joker
+	{0x1f18e, 0x1f18e}, // AB button
+	{0x1f191, 0x1f19a}, // squared words
+	{0x1f1e6, 0x1f1ff}, // regional indicators (flags)
+	{0x1f200, 0x1f251}, // enclosed ideographs
+	{0x1f300, 0x1f64f}, // pictographs, emoticons
+	{0x1f680, 0x1f6ff}, // transport and map
+	{0x1f7e0, 0x1f7eb}, // coloured circles and squares
+	{0x1f90c, 0x1f9ff}, // supplemental symbols and pictographs
+	{0x1fa70, 0x1faff}, // symbols and pictographs extended A
+	{0x20000, 0x2fffd}, // CJK extension B onwards
+	{0x30000, 0x3fffd}, // CJK extension G onwards
+}
+
+func isWideRune(r rune) bool {
+	if r < 0x1100 {
+		return false
+	}
+	lo, hi := 0, len(wideRanges)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch {
+		case r < wideRanges[mid].lo:
+			hi = mid
+		case r > wideRanges[mid].hi:
+			lo = mid + 1
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+func isZeroWidthRune(r rune) bool {
+	switch {
+	case r == zeroWidthJoiner, r == variationSelect:
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff: // emoji skin tone modifiers
+		return true
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Cc):
+		return true
+	}
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// runeWidth returns the number of terminal cells a single rune occupies
+// when it is not part of a longer sequence.
+func runeWidth(r rune) int {
+	switch {
+	case isZeroWidthRune(r):
+		return 0
+	case isWideRune(r):
+		return 2
+	}
+	return 1
+}
+
+// displayWidth returns how many terminal cells s takes up.
+// A rune that follows a zero-width joiner is part of the same emoji and adds
+// nothing, a pair of regional indicators is a single two-cell flag, and a
+// variation selector turns a narrow symbol like "❤" into a two-cell emoji.
+func displayWidth(s string) int {
+	if strings.IndexByte(s, escape) >= 0 {
+		s = stripANSI(s)
+	}
+	width := 0
+	last := 0
+	joined := false
+	pendingFlag := false
+	for _, r := range s {
+		if r == variationSelect && last == 1 {
+			width++
+			last = 2
+			continue
+		}
+		switch {
+		case joined:
+			joined = false
+			if !isZeroWidthRune(r) {
+				continue
+			}
+		case isRegionalIndicator(r):
+			if pendingFlag {
+				pendingFlag = false
+				continue
+			}
+			pendingFlag = true
+			width += 2
+			last = 2
+			continue
+		}
+		pendingFlag = false
+		if r == zeroWidthJoiner {
+			joined = true
+			continue
+		}
+		last = runeWidth(r)
+		width += last
+	}
+	return width
+}
+
+// truncateWidth cuts s so that it fits in width cells. Zero-width runes that
+// belong to the last kept character are kept with it.
+func truncateWidth(s string, width int) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+	head := s[:cutWidth(s, width)]
+	if strings.IndexByte(s, escape) >= 0 {
+		// The code that switched a color off may have been cut off too.
+		head += ansiReset
+	}
+	return head
+}
+
+// cutWidth is the byte offset at which truncateWidth cuts s, s[:n] is what
+// fits in width cells without the reset.
+func cutWidth(s string, width int) int {
+	used := 0
+	i := 0
+	for i < len(s) {
+		// Take one visible character plus everything glued onto it.
+		end := clusterEnd(s, i)
+		w := displayWidth(s[i:end])
+		if used+w > width {
+			break
+		}
+		used += w
+		i = end
+	}
+	return i
+}
+
+// clusterEnd returns the byte offset where the character starting at i ends,
+// including trailing combining marks, joiners and flag pairs.
+func clusterEnd(s string, i int) int {
+	if s[i] == escape {
+		return i + ansiLen(s[i:])
+	}
+	r, size := utf8.DecodeRuneInString(s[i:])
+	end := i + size
+	if isRegionalIndicator(r) && end < len(s) {
+		if next, n := utf8.DecodeRuneInString(s[end:]); isRegionalIndicator(next) {
+			end += n
+		}
+		return end
+	}
+	for end < len(s) {
+		next, n := utf8.DecodeRuneInString(s[end:])
+		switch {
+		case next == zeroWidthJoiner:
+			end += n
+			if end < len(s) {
+				_, n = utf8.DecodeRuneInString(s[end:])
+				end += n
+			}
+		case isZeroWidthRune(next):
+			end += n
+		default:
+			return end
+		}
+	}
+	return end
+}
+
+// padRight pads s with spaces until it takes up width cells.
+// This is what "%-*s" would do if fmt counted cells instead of runes.
+func padRight(s string, width int) string {
+	if gap := width - displayWidth(s); gap > 0 {
+		return s + strings.Repeat(" ", gap)
+	}
+	return s
+}
+
+// alignCell pads s with spaces to width cells, on the side(s) align asks for.
+// AlignAuto is treated as left, callers resolve it before getting here.
+func alignCell(s string, width int, align Alignment) string {
+	gap := width - displayWidth(s)
+	if gap <= 0 {
+		return s
+	}
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", gap) + s
+	case AlignCenter:
+		left := gap / 2
+		return strings.Repeat(" ", left) + s + strings.Repeat(" ", gap-left)
+	}
+	return s + strings.Repeat(" ", gap)
+}
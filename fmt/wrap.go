@@ -0,0 +1,211 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Overflow is what happens to a cell that is wider than its column.
+type Overflow int
+
+const (
+	OverflowTruncate Overflow = iota // cut the cell and end it with "…"
+	OverflowWrap                     // break the cell into several lines at spaces
+)
+
+const ellipsis = "…"
+
+// cellWidth is the width of the widest line of a cell,
+// a cell with "\n" in it is drawn as several lines.
+func cellWidth(cell string) int {
+	w := 0
+	for _, line := range splitLines(cell) {
+		w = max(w, displayWidth(line))
+	}
+	return w
+}
+
+func splitLines(cell string) []string {
+	return strings.Split(strings.ReplaceAll(cell, "\r\n", "\n"), "\n")
+}
+
+// cellLines breaks a cell into the physical lines it takes up in a column of
+// the given width. Every returned line is at most width cells wide.
+func cellLines(cell string, width int, overflow Overflow) []string {
+	var lines []string
+	for _, line := range splitLines(cell) {
+		if overflow == OverflowWrap {
+			lines = append(lines, wordWrap(line, width)...)
+		} else {
+			lines = append(lines, truncateEllipsis(line, width))
+		}
+	}
+	return lines
+}
+
+// truncateEllipsis cuts s to width cells, using the last cell for "…"
+// so the reader can tell something was cut off.
+func truncateEllipsis(s string, width int) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	return truncateWidth(s, width-1) + ellipsis
+}
+
+// wordWrap breaks s into lines of at most width cells, at spaces where it can.
+// A word that is longer than a whole line is broken in the middle.
+func wordWrap(s string, width int) []string {
+	if width <= 0 {
+		return []string{""}
+	}
+	if displayWidth(s) <= width {
+		return []string{s}
+	}
+
+	var lines []string
+	line, lineWidth := "", 0
+	for _, word := range strings.FieldsFunc(s, unicode.IsSpace) {
+		ww := displayWidth(word)
+		switch {
+		case lineWidth == 0:
+		case lineWidth+1+ww <= width:
+			line += " "
+			lineWidth++
+		default:
+			lines = append(lines, line)
+			line, lineWidth = "", 0
+		}
+
+		// A word that doesn't fit even on an empty line is split over as
+		// many lines as it needs. line is always empty here.
+		for ww > width {
+			n := cutWidth(word, width)
+			if displayWidth(word[:n]) == 0 {
+				// a two-cell character in a one-cell column
+				n = clusterEnd(word, n)
+			}
+			head := word[:n]
+			if strings.IndexByte(word, escape) >= 0 {
+				// end the color on this line and start it again on the next
+				lines = append(lines, head+ansiReset)
+				word = activeSGR(head) + word[n:]
+			} else {
+				lines = append(lines, head)
+				word = word[n:]
+			}
+			ww = displayWidth(word)
+		}
+		line += word
+		lineWidth += ww
+	}
+	if line == "" && len(lines) > 0 {
+		// the last word was split exactly at a line end
+		return lines
+	}
+	return append(lines, line)
+}
+
+// activeSGR is the color codes in s that are still on at its end, the
+// ones after the last reset.
+func activeSGR(s string) string {
+	var codes strings.Builder
+	for i := strings.IndexByte(s, escape); i >= 0; i = strings.IndexByte(s, escape) {
+		seq := s[i : i+ansiLen(s[i:])]
+		switch {
+		case seq == ansiReset || seq == "\x1b[m":
+			codes.Reset()
+		case strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m"):
+			codes.WriteString(seq)
+		}
+		s = s[i+len(seq):]
+	}
+	return codes.String()
+}
+
+// overhead is how many cells the frame takes up on a line of n columns.
+func (b *BorderStyle) overhead(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return displayWidth(b.Left) + displayWidth(b.Right) +
+		(n-1)*displayWidth(b.Middle) + n*2*b.Padding
+}
+
+// shrinkWidths makes widths add up to at most budget by taking cells away
+// from every column in proportion to its width, so a 40-cell column gives up
+// four times as much as a 10-cell one. No column goes below its minimum.
+func shrinkWidths(widths, minimums []int, budget int) []int {
+	out := append([]int(nil), widths...)
+	total := 0
+	for _, w := range out {
+		total += w
+	}
+	if total <= budget {
+		return out
+	}
+
+	fixed := make([]bool, len(out))
+	for {
+		// Spread what is left over the columns that can still shrink.
+		free, freeTotal := budget, 0
+		for i, w := range widths {
+			if fixed[i] {
+				free -= out[i]
+			} else {
+				freeTotal += w
+			}
+		}
+		if freeTotal == 0 || free <= 0 {
+			return out
+		}
+
+		changed := false
+		used := 0
+		for i, w := range widths {
+			if fixed[i] {
+				continue
+			}
+			out[i] = w * free / freeTotal
+			if out[i] < minimums[i] {
+				out[i] = minimums[i]
+				fixed[i] = true
+				changed = true
+			}
+			used += out[i]
+		}
+		if changed {
+			// Someone hit their minimum, so the others have to share less room.
+			continue
+		}
+
+		// Integer division rounds down, hand the spare cells back out
+		// starting from the first column.
+		for i := 0; used < free && i < len(out); i++ {
+			if !fixed[i] && out[i] < widths[i] {
+				out[i]++
+				used++
+			}
+		}
+		return out
+	}
+}
+
+// fitToWidth shrinks the column widths of a table so the whole line,
+// frame included, fits in budget cells. Columns keep at least their MinWidth,
+// or 3 cells when that isn't set, enough for one character and an ellipsis.
+func (t TableData) fitToWidth(widths []int, border *BorderStyle, budget int) []int {
+	if budget <= 0 {
+		return widths
+	}
+	minimums := make([]int, len(widths))
+	for i, w := range widths {
+		minimums[i] = min(w, 3)
+		if m := t.column(i).MinWidth; m > 0 {
+			minimums[i] = min(w, m)
+		}
+	}
+	return shrinkWidths(widths, minimums, budget-border.overhead(len(widths)))
+}
@@ -0,0 +1,35 @@
+// Package analyzers has the go/analysis analyzers behind stdlibvet.
+// Each one looks for a single kind of mistake, so they can be turned on and
+// off one by one, or run by any other go/analysis driver.
+package analyzers
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// All is every analyzer in the package.
+var All = []*analysis.Analyzer{
+	Signature,
+	Escape,
+	Fatal,
+	WriteResult,
+}
+
+// callee returns the function or method call calls, or nil for calls of
+// function values, conversions and builtins.
+func callee(info *types.Info, call *ast.CallExpr) *types.Func {
+	fn, _ := typeutil.Callee(info, call).(*types.Func)
+	return fn
+}
+
+// returnsError reports whether the last result of fn is an error.
+func returnsError(fn *types.Func) bool {
+	results := fn.Type().(*types.Signature).Results()
+	return results.Len() > 0 && types.Identical(results.At(results.Len()-1).Type(), errorType)
+}
+
+var errorType = types.Universe.Lookup("error").Type()
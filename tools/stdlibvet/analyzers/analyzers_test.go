@@ -0,0 +1,24 @@
+package analyzers_test
+
+import (
+	"testing"
+
+	"github.com/Varsilias/learning-go-stdlib/tools/stdlibvet/analyzers"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestSignature(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), analyzers.Signature, "signature")
+}
+
+func TestEscape(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzers.Escape, "escape")
+}
+
+func TestFatal(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzers.Fatal, "fatal")
+}
+
+func TestWriteResult(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), analyzers.WriteResult, "writeresult")
+}
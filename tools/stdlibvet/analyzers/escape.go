@@ -0,0 +1,102 @@
+package analyzers
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Escape reports escape sequences typed the wrong way round in strings
+// passed to fmt and log:
+//
+//	fmt.Printf("Error: %v/n", err)  // prints "/n", meant "\n"
+//	fmt.Printf(`%v\n`, err)         // raw string, prints a backslash and an n
+var Escape = &analysis.Analyzer{
+	Name:     "escape",
+	Doc:      `report "/n", "/t" and "/r" typed for "\n", "\t" and "\r", and escapes in raw format strings, in calls to fmt and log`,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runEscape,
+}
+
+// slashEscape is a forward slash escape at the end of a string or before a
+// space, a verb or a real escape, "/news" and "a/tb" are left alone.
+// It runs on the source of the literal, quotes included.
+var slashEscape = regexp.MustCompile(`/[ntr][" \t%\\]`)
+
+// printPackages are the packages whose Print-like functions and methods are checked.
+var printPackages = map[string]bool{"fmt": true, "log": true}
+
+func runEscape(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn := callee(pass.TypesInfo, call)
+		if fn == nil || fn.Pkg() == nil || !printPackages[fn.Pkg().Path()] {
+			return
+		}
+		isFormat := strings.HasSuffix(fn.Name(), "f")
+		for _, arg := range call.Args {
+			lit, ok := arg.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				continue
+			}
+			if strings.HasPrefix(lit.Value, "`") {
+				if isFormat {
+					checkRawFormat(pass, lit)
+				}
+				continue
+			}
+			checkSlashEscapes(pass, lit)
+		}
+	})
+	return nil, nil
+}
+
+func checkSlashEscapes(pass *analysis.Pass, lit *ast.BasicLit) {
+	// The literal's source has the same "/n" as its value, so offsets into
+	// lit.Value are positions in the file.
+	for _, m := range slashEscape.FindAllStringIndex(lit.Value, -1) {
+		pos := lit.Pos() + token.Pos(m[0])
+		typo := lit.Value[m[0] : m[0]+2]
+		fixed := `\` + typo[1:]
+		pass.Report(analysis.Diagnostic{
+			Pos:     pos,
+			End:     pos + 2,
+			Message: "\"" + typo + "\" looks like a typo for \"" + fixed + "\"",
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message:   "Replace " + typo + " with " + fixed,
+				TextEdits: []analysis.TextEdit{{Pos: pos, End: pos + 2, NewText: []byte(fixed)}},
+			}},
+		})
+	}
+}
+
+// checkRawFormat reports `\n` in a raw string format, which prints a
+// backslash and an n. The fix turns the whole literal into a quoted string.
+func checkRawFormat(pass *analysis.Pass, lit *ast.BasicLit) {
+	raw := lit.Value[1 : len(lit.Value)-1]
+	for _, esc := range []string{`\n`, `\t`, `\r`} {
+		i := strings.Index(raw, esc)
+		if i < 0 {
+			continue
+		}
+		pos := lit.Pos() + 1 + token.Pos(i)
+		unescaped := strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\r`, "\r").Replace(raw)
+		pass.Report(analysis.Diagnostic{
+			Pos:     pos,
+			End:     pos + 2,
+			Message: "raw string format prints " + esc + " as a backslash and a letter, use a quoted string",
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message:   "Use a quoted string",
+				TextEdits: []analysis.TextEdit{{Pos: lit.Pos(), End: lit.End(), NewText: []byte(strconv.Quote(unescaped))}},
+			}},
+		})
+		return
+	}
+}
@@ -0,0 +1,216 @@
+package analyzers
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Fatal reports statements that come right after a call that never returns:
+//
+//	log.Fatalf("could not open %s", path)
+//	os.Exit(1) // never runs, log.Fatalf already exited
+//
+// The suggested fix removes them, but keeps a final return the compiler
+// still wants, and isn't offered when a variable or import would lose its
+// last use.
+var Fatal = &analysis.Analyzer{
+	Name:     "fatal",
+	Doc:      "report unreachable statements after log.Fatal, log.Panic and os.Exit",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runFatal,
+}
+
+// noReturn are the functions that end the goroutine or the program.
+var noReturn = map[string]bool{
+	"os.Exit":                   true,
+	"log.Fatal":                 true,
+	"log.Fatalf":                true,
+	"log.Fatalln":               true,
+	"log.Panic":                 true,
+	"log.Panicf":                true,
+	"log.Panicln":               true,
+	"(*log.Logger).Fatal":       true,
+	"(*log.Logger).Fatalf":      true,
+	"(*log.Logger).Fatalln":     true,
+	"(*log.Logger).Panic":       true,
+	"(*log.Logger).Panicf":      true,
+	"(*log.Logger).Panicln":     true,
+	"runtime.Goexit":            true,
+	"(*testing.common).Fatal":   true,
+	"(*testing.common).Fatalf":  true,
+	"(*testing.common).FailNow": true,
+}
+
+func runFatal(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodes := []ast.Node{(*ast.BlockStmt)(nil), (*ast.CaseClause)(nil), (*ast.CommClause)(nil)}
+	inspect.WithStack(nodes, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		var list []ast.Stmt
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			list = n.List
+		case *ast.CaseClause:
+			list = n.Body
+		case *ast.CommClause:
+			list = n.Body
+		}
+		for i, stmt := range list {
+			name, ok := noReturnCall(pass, stmt)
+			if !ok {
+				continue
+			}
+			rest := unreachable(list[i+1:], hasResults(pass, stack))
+			if len(rest) == 0 {
+				return true
+			}
+			diag := analysis.Diagnostic{
+				Pos:     rest[0].Pos(),
+				End:     rest[0].End(),
+				Message: "unreachable code: " + name + " never returns",
+			}
+			if removable(pass, stack, rest) {
+				diag.SuggestedFixes = []analysis.SuggestedFix{{
+					Message:   "Remove the unreachable statements",
+					TextEdits: []analysis.TextEdit{{Pos: stmt.End(), End: lineEnd(pass, stack, rest[len(rest)-1])}},
+				}}
+			}
+			pass.Report(diag)
+			return true
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// unreachable is the part of rest that can't run and can go. It ends before
+// a label, which a goto elsewhere may jump to, and before a final return or
+// panic that a function with results still needs to compile.
+func unreachable(rest []ast.Stmt, results bool) []ast.Stmt {
+	for i, stmt := range rest {
+		if _, ok := stmt.(*ast.LabeledStmt); ok {
+			rest = rest[:i]
+			break
+		}
+	}
+	if results && len(rest) > 0 && terminates(rest[len(rest)-1]) {
+		rest = rest[:len(rest)-1]
+	}
+	return rest
+}
+
+// terminates reports whether stmt ends the function, like a return.
+func terminates(stmt ast.Stmt) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return stmt.Tok == token.GOTO
+	case *ast.ExprStmt:
+		call, ok := stmt.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		id, ok := call.Fun.(*ast.Ident)
+		return ok && id.Name == "panic"
+	}
+	return false
+}
+
+// lineEnd is the end of stmt, or of the comment after it on the same line,
+// which goes with the statement.
+func lineEnd(pass *analysis.Pass, stack []ast.Node, stmt ast.Stmt) token.Pos {
+	file, ok := stack[0].(*ast.File)
+	if !ok {
+		return stmt.End()
+	}
+	line := pass.Fset.Position(stmt.End()).Line
+	for _, group := range file.Comments {
+		if group.Pos() >= stmt.End() && pass.Fset.Position(group.Pos()).Line == line {
+			return group.End()
+		}
+	}
+	return stmt.End()
+}
+
+// enclosingFunc is the innermost function in stack.
+func enclosingFunc(stack []ast.Node) ast.Node {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i].(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return stack[i]
+		}
+	}
+	return nil
+}
+
+// hasResults reports whether the function the statements are in returns anything.
+func hasResults(pass *analysis.Pass, stack []ast.Node) bool {
+	var typ *ast.FuncType
+	switch fn := enclosingFunc(stack).(type) {
+	case *ast.FuncDecl:
+		typ = fn.Type
+	case *ast.FuncLit:
+		typ = fn.Type
+	default:
+		return false
+	}
+	return typ.Results != nil && len(typ.Results.List) > 0
+}
+
+// removable reports whether deleting rest leaves code that compiles: no
+// local variable or import may lose its last use.
+func removable(pass *analysis.Pass, stack []ast.Node, rest []ast.Stmt) bool {
+	from, to := rest[0].Pos(), rest[len(rest)-1].End()
+	inside := func(pos token.Pos) bool { return pos >= from && pos < to }
+
+	fn := enclosingFunc(stack)
+	needed := map[types.Object]bool{}
+	for id, obj := range pass.TypesInfo.Uses {
+		if !inside(id.Pos()) {
+			continue
+		}
+		switch obj := obj.(type) {
+		case *types.Var:
+			// a variable of the function, declared before the removed code
+			if fn != nil && obj.Pos() >= fn.Pos() && obj.Pos() < from {
+				needed[obj] = true
+			}
+		case *types.PkgName:
+			needed[obj] = true
+		}
+	}
+	if len(needed) == 0 {
+		return true
+	}
+	for id, obj := range pass.TypesInfo.Uses {
+		if needed[obj] && !inside(id.Pos()) {
+			delete(needed, obj)
+		}
+	}
+	return len(needed) == 0
+}
+
+// noReturnCall reports whether stmt is a call to one of the noReturn functions.
+func noReturnCall(pass *analysis.Pass, stmt ast.Stmt) (string, bool) {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return "", false
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+	fn := callee(pass.TypesInfo, call)
+	if fn == nil || !noReturn[fn.FullName()] {
+		return "", false
+	}
+	return fn.FullName(), true
+}
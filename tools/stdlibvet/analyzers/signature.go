@@ -0,0 +1,98 @@
+package analyzers
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Signature reports methods that are named like the method of a standard
+// interface and almost have its signature, e.g.
+//
+//	func (v ValidationError) Error() error
+//
+// compiles fine, but ValidationError is not an error and fmt never calls it.
+// Methods that only share the name, like Write(w io.Writer, v any) error,
+// are something else and are left alone.
+var Signature = &analysis.Analyzer{
+	Name:     "signature",
+	Doc:      "report Error, String, Read and Write methods whose signature doesn't match error, fmt.Stringer, io.Reader or io.Writer",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runSignature,
+}
+
+// wantMethod is the signature a method must have to implement iface.
+type wantMethod struct {
+	params, results []types.Type
+	signature       string
+	iface           string
+}
+
+var (
+	byteSlice  = types.NewSlice(types.Typ[types.Byte])
+	stringType = types.Typ[types.String]
+	intType    = types.Typ[types.Int]
+)
+
+var wantMethods = map[string]wantMethod{
+	"Error":  {nil, []types.Type{stringType}, "Error() string", "error"},
+	"String": {nil, []types.Type{stringType}, "String() string", "fmt.Stringer"},
+	"Read":   {[]types.Type{byteSlice}, []types.Type{intType, errorType}, "Read(p []byte) (n int, err error)", "io.Reader"},
+	"Write":  {[]types.Type{byteSlice}, []types.Type{intType, errorType}, "Write(p []byte) (n int, err error)", "io.Writer"},
+}
+
+func runSignature(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		decl := n.(*ast.FuncDecl)
+		want, ok := wantMethods[decl.Name.Name]
+		if decl.Recv == nil || !ok {
+			return
+		}
+		fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+		if !ok {
+			return
+		}
+		sig := fn.Type().(*types.Signature)
+		if !sig.Variadic() && identical(sig.Params(), want.params) && identical(sig.Results(), want.results) {
+			return
+		}
+		if !nearMiss(sig, want) {
+			return
+		}
+		recv := types.TypeString(sig.Recv().Type(), types.RelativeTo(pass.Pkg))
+		pass.Reportf(decl.Name.Pos(), "method %s.%s has signature %s, want %s to implement %s",
+			recv, decl.Name.Name, types.TypeString(sig, types.RelativeTo(pass.Pkg)), want.signature, want.iface)
+	})
+	return nil, nil
+}
+
+// nearMiss reports whether sig looks like an attempt at want: the same
+// number of parameters, and for Read and Write a byte slice or a string.
+// Write(record []string) error, like encoding/csv has, is a different method.
+func nearMiss(sig *types.Signature, want wantMethod) bool {
+	if sig.Params().Len() != len(want.params) {
+		return false
+	}
+	if len(want.params) == 0 {
+		// Error() error, String() (string, error)
+		return true
+	}
+	param := sig.Params().At(0).Type().Underlying()
+	return types.Identical(param, byteSlice) || types.Identical(param, stringType)
+}
+
+func identical(tuple *types.Tuple, want []types.Type) bool {
+	if tuple.Len() != len(want) {
+		return false
+	}
+	for i, t := range want {
+		if !types.Identical(tuple.At(i).Type(), t) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,17 @@
+package escape
+
+import (
+	"fmt"
+	"log"
+)
+
+func slashes(err error) {
+	fmt.Printf("Error: %v/n", err) // want `"/n" looks like a typo for "\\n"`
+	log.Printf("a/t%d", 1)         // want `"/t" looks like a typo for "\\t"`
+	fmt.Println("see /news and a/tb")
+}
+
+func raw(err error) {
+	fmt.Printf(`%v\n`, err) // want `raw string format prints \\n as a backslash and a letter`
+	fmt.Println(`C:\new`)
+}
@@ -0,0 +1,56 @@
+package fatal
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+func exitAfterFatal(path string) {
+	if path == "" {
+		log.Fatalf("no path")
+		os.Exit(1) // want "unreachable code: log.Fatalf never returns"
+	}
+}
+
+// The return is needed to compile, only the print goes.
+func returnAfterFatal() int {
+	log.Fatal("x")
+	fmt.Println("bye") // want "unreachable code: log.Fatal never returns"
+	return 0
+}
+
+// Nothing but the needed return, nothing to report.
+func onlyReturn() int {
+	log.Fatal("x")
+	return 0
+}
+
+func panicAfterExit() string {
+	os.Exit(2)
+	panic("unreachable")
+}
+
+// Removing the print would leave s unused, so there is no fix.
+func lastUse() {
+	s := "x"
+	log.Fatal()
+	fmt.Println(s) // want "unreachable code: log.Fatal never returns"
+}
+
+// Removing the call would leave the strings import unused, so there is no fix.
+func lastImportUse() {
+	log.Panic("x")
+	strings.ToUpper("x") // want "unreachable code: log.Panic never returns"
+}
+
+// A label may be jumped to, the code after it isn't unreachable.
+func label(n int) {
+	if n > 0 {
+		goto end
+	}
+	log.Fatal("x")
+end:
+	fmt.Println(n)
+}
@@ -0,0 +1,36 @@
+package signature
+
+import "io"
+
+type ValidationError struct{ msg string }
+
+func (v ValidationError) Error() error { return nil } // want `method ValidationError.Error has signature func\(\) error, want Error\(\) string to implement error`
+
+type Person struct{}
+
+func (p Person) String() (string, error) { return "", nil } // want `method Person.String has signature`
+
+type counter struct{}
+
+func (c *counter) Write(s string) error { return nil } // want `method \*counter.Write has signature .* to implement io.Writer`
+
+func (c *counter) Read(p []byte) int { return 0 } // want `method \*counter.Read has signature .* to implement io.Reader`
+
+// Only the names match, these are different methods.
+
+type printer struct{}
+
+func (printer) Write(w io.Writer, v any) error { return nil }
+
+type records struct{}
+
+func (records) Write(record []string) error { return nil }
+
+func (records) String(indent int) string { return "" }
+
+// Right signatures.
+
+type ok struct{}
+
+func (ok) Error() string                     { return "" }
+func (ok) Write(p []byte) (n int, err error) { return 0, nil }
@@ -0,0 +1,32 @@
+package writeresult
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"os"
+	"strings"
+)
+
+func ignored(w io.Writer, r io.Reader) {
+	w.Write([]byte("x"))  // want `result of w.Write is ignored`
+	io.Copy(w, r)         // want `result of io.Copy is ignored`
+	io.WriteString(w, "") // want `result of io.WriteString is ignored`
+	_, _ = w.Write(nil)
+}
+
+func buffers(r io.Reader) {
+	var buf bytes.Buffer
+	buf.Write([]byte("x"))
+	buf.WriteString("x")
+	buf.WriteByte('x')
+	buf.WriteRune('x')
+	buf.WriteTo(os.Stdout) // want `result of buf.WriteTo is ignored`
+	buf.ReadFrom(r)        // want `result of buf.ReadFrom is ignored`
+
+	var b strings.Builder
+	b.WriteString("x")
+
+	h := sha256.New()
+	h.Write([]byte("x"))
+}
@@ -0,0 +1,105 @@
+package analyzers
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// WriteResult reports Write and Copy calls whose results are thrown away:
+//
+//	w.Write([]byte(message))  // a failed or short write goes unnoticed
+//	io.Copy(dst, src)
+//
+// Assigning the results to _ says it's on purpose and is not reported.
+var WriteResult = &analysis.Analyzer{
+	Name:     "writeresult",
+	Doc:      "report calls of Write methods, io.Copy and io.WriteString whose error result is ignored",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runWriteResult,
+}
+
+// writeFuncs are the functions that are reported by name.
+var writeFuncs = map[string]bool{
+	"io.Copy":        true,
+	"io.CopyN":       true,
+	"io.CopyBuffer":  true,
+	"io.WriteString": true,
+}
+
+// writeMethods are the methods that are reported on any type.
+var writeMethods = map[string]bool{
+	"Write":       true,
+	"WriteString": true,
+	"WriteAt":     true,
+	"WriteTo":     true,
+	"ReadFrom":    true,
+}
+
+// neverFail are types whose Write methods are documented to always return a
+// nil error. Their WriteTo and ReadFrom pass on the errors of the other side,
+// those are still reported.
+var neverFail = map[string]bool{
+	"bytes.Buffer":    true,
+	"strings.Builder": true,
+	"hash.Hash":       true,
+	"hash.Hash32":     true,
+	"hash.Hash64":     true,
+}
+
+func runWriteResult(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.ExprStmt)(nil)}, func(n ast.Node) {
+		call, ok := n.(*ast.ExprStmt).X.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		fn := callee(pass.TypesInfo, call)
+		if fn == nil || !returnsError(fn) {
+			return
+		}
+
+		recv := fn.Type().(*types.Signature).Recv()
+		switch {
+		case recv == nil && !writeFuncs[fn.FullName()]:
+			return
+		case recv != nil && !writeMethods[fn.Name()]:
+			return
+		case recv != nil && neverFails(pass, fn, call):
+			return
+		}
+		pass.ReportRangef(call, "result of %s is ignored, a failed or short write goes unnoticed", types.ExprString(call.Fun))
+	})
+	return nil, nil
+}
+
+// neverFailMethods are the methods of the neverFail types that can't fail.
+var neverFailMethods = map[string]bool{
+	"Write":       true,
+	"WriteString": true,
+	"WriteByte":   true,
+	"WriteRune":   true,
+}
+
+// neverFails reports whether the call is a Write method of one of the neverFail types.
+func neverFails(pass *analysis.Pass, fn *types.Func, call *ast.CallExpr) bool {
+	if !neverFailMethods[fn.Name()] {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	t := pass.TypesInfo.TypeOf(sel.X)
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return neverFail[named.Obj().Pkg().Path()+"."+named.Obj().Name()]
+}
@@ -0,0 +1,24 @@
+// stdlibvet checks Go code for the mistakes made while learning the standard
+// library: methods that almost implement error, fmt.Stringer, io.Reader or
+// io.Writer, "/n" typed instead of "\n", code after log.Fatal and ignored
+// Write and Copy results.
+//
+// It is its own module, so the lessons keep building with nothing but the
+// standard library. Install it, then run it from the repository root like
+// go vet:
+//
+//	(cd tools/stdlibvet && go install .)
+//	stdlibvet ./...
+//	stdlibvet -fix ./...          // apply the suggested fixes
+//	stdlibvet -escape=false ./... // everything but the escape check
+//	go vet -vettool=$(go env GOPATH)/bin/stdlibvet ./...
+package main
+
+import (
+	"github.com/Varsilias/learning-go-stdlib/tools/stdlibvet/analyzers"
+	"golang.org/x/tools/go/analysis/multichecker"
+)
+
+func main() {
+	multichecker.Main(analyzers.All...)
+}